@@ -2,52 +2,139 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/marcopaulosilva/poc_devin/internal/config"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
 	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
-	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/health"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/metrics"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/tracing"
 	"github.com/marcopaulosilva/poc_devin/internal/interfaces/api"
+	"github.com/marcopaulosilva/poc_devin/internal/interfaces/cache"
 	httpRepo "github.com/marcopaulosilva/poc_devin/internal/interfaces/http"
 )
 
 func main() {
-	log := logger.NewConsoleLogger()
-	httpClient := client.NewHTTPClient(10 * time.Second)
-	
-	apiKey := os.Getenv("RIOT_API_KEY")
-	if apiKey == "" {
-		log.Error("RIOT_API_KEY environment variable not set")
+	cfg, err := config.LoadAPI(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	log := cfg.Log.NewLogger().With("service", "api")
+	if err != nil {
+		log.Error("%v", err)
 		os.Exit(1)
 	}
+	tracer, err := tracing.New("movement-speed-api", cfg.Tracing.Exporter, cfg.Tracing.File)
+	if err != nil {
+		log.Error("Failed to set up tracing: %v", err)
+		os.Exit(1)
+	}
+	registry := metrics.NewRegistry()
+	clientMetrics := metrics.NewClientMetrics(registry)
+	breakers := client.NewCircuitBreakers(client.DefaultBreakerConfig(), log)
+	httpClient := client.NewHTTPClient(10*time.Second,
+		client.WithRetryPolicy(client.DefaultRetryPolicy()),
+		client.WithCircuitBreakers(breakers),
+		client.WithRequestObserver(clientMetrics),
+		client.WithTracer(tracer),
+	)
 	
-	baseURL := "https://na1.api.riotgames.com"
-	
-	championRepo := httpRepo.NewChampionRepository(httpClient, log, baseURL, apiKey)
-	championUseCase := usecases.NewChampionUseCase(championRepo)
+	championRepo := httpRepo.NewChampionRepository(httpClient, log, httpRepo.ChampionRepositoryConfig{
+		Version: cfg.DataDragon.Version,
+		Workers: cfg.DataDragon.Workers,
+		Mode:    httpRepo.FetchMode(cfg.DataDragon.Mode),
+	})
+	cachedChampionRepo := cache.NewChampionRepository(championRepo, log, cache.ChampionRepositoryConfig{
+		TTL: cfg.CacheTTL,
+	})
+	championUseCase := usecases.NewChampionUseCase(cachedChampionRepo)
 	
+	newRiotClient := func() client.HTTPClient {
+		return client.NewHTTPClient(10*time.Second,
+			client.WithRetryPolicy(client.DefaultRetryPolicy()),
+			client.WithCircuitBreakers(breakers),
+			client.WithRateLimiter(client.NewRateLimiter(client.RiotDevelopmentRateLimits...)),
+			client.WithRequestObserver(clientMetrics),
+			client.WithTracer(tracer),
+		)
+	}
+	riotRepo := httpRepo.NewRiotRepository(newRiotClient, log, httpRepo.RiotRepositoryConfig{
+		URLTemplate: cfg.RiotURLTemplate,
+		APIKey:      string(cfg.RiotAPIKey),
+	})
+	playerUseCase := usecases.NewPlayerUseCase(riotRepo, cachedChampionRepo)
+
 	movementSpeedHandler := api.NewMovementSpeedHandler(championUseCase, log)
+	championRankHandler := api.NewChampionRankHandler(championUseCase, log)
+	championHandler := api.NewChampionHandler(championUseCase, log)
+	playerMasteryHandler := api.NewPlayerMasteryHandler(playerUseCase, log)
+	healthHandler := api.NewHealthHandler(breakers, log)
 	
+	timeouts, err := api.ParseRouteTimeouts(cfg.RouteTimeouts, cfg.RequestTimeout)
+	if err != nil {
+		log.Error("Invalid ROUTE_TIMEOUTS value: %v", err)
+		os.Exit(1)
+	}
+
+	serverMetrics := metrics.NewServerMetrics(registry)
 	mux := http.NewServeMux()
-	mux.HandleFunc("/api/champions/movement-speed", movementSpeedHandler.GetChampionsByMovementSpeed)
+	handle := func(pattern string, handler http.HandlerFunc) {
+		mux.Handle(pattern, serverMetrics.Instrument(pattern, tracer.Handler(pattern, api.WithTimeout(timeouts.For(pattern), handler))))
+	}
+	handle(api.MovementSpeedPath, movementSpeedHandler.GetChampionsByMovementSpeed)
+	handle(api.ChampionRankPath, championRankHandler.GetChampionRanking)
+	handle(api.ChampionPathPrefix, championHandler.GetChampion)
+	handle(api.PlayerMasteryPath, playerMasteryHandler.GetTopMasteryChampions)
 	
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	// Readiness needs the champion cache of the default locale, which every
+	// endpoint uses, so warm it right away and until it succeeds. Open
+	// circuits only degrade the pod, since the cache keeps serving stale data
+	// while upstream is down.
+	readiness := health.NewProbe(health.DefaultTimeout,
+		health.Check{
+			Name: "champion_cache",
+			Checker: health.CheckerFunc(func(ctx context.Context) error {
+				if !cachedChampionRepo.Warm(entities.DefaultLocale) {
+					return fmt.Errorf("champions in %s not loaded yet", entities.DefaultLocale)
+				}
+				return nil
+			}),
+			Critical: true,
+		},
+		health.Check{Name: "upstream", Checker: health.CircuitChecker(breakers)},
+	)
+	go func() {
+		for {
+			_, err := cachedChampionRepo.GetAllChampions(context.Background(), entities.DefaultLocale)
+			if err == nil {
+				return
+			}
+			log.Warn("Failed to warm champion cache, retrying in 10s: %v", err)
+			time.Sleep(10 * time.Second)
+		}
+	}()
+
+	mux.HandleFunc("/health", healthHandler.GetHealth)
+	mux.Handle("/livez", health.NewProbe(health.DefaultTimeout).Handler())
+	mux.Handle("/readyz", readiness.Handler())
+	mux.Handle("/metrics", registry.Handler())
 	
-	port := 8080
-	server := api.NewServer(port, mux, log)
+	server := api.NewServer(cfg.Port, api.WithRequestID(mux), log, api.WithRequestTimeout(timeouts.Max()))
 	
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	
 	go func() {
-		log.Info("Starting REST API server on port %d", port)
+		log.Info("Starting REST API server on port %d", cfg.Port)
 		if err := server.Start(); err != nil && err != http.ErrServerClosed {
 			log.Error("Failed to start server: %v", err)
 			os.Exit(1)
@@ -63,6 +150,9 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Error("Server shutdown failed: %v", err)
 	}
+	if err := tracer.Shutdown(ctx); err != nil {
+		log.Error("Failed to export traces: %v", err)
+	}
 	
 	log.Success("Server gracefully stopped")
 }
@@ -2,97 +2,165 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/marcopaulosilva/poc_devin/internal/config"
 	"github.com/marcopaulosilva/poc_devin/internal/domain/repositories"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
 	dbInfra "github.com/marcopaulosilva/poc_devin/internal/infrastructure/db"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/health"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/metrics"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/tracing"
 	"github.com/marcopaulosilva/poc_devin/internal/interfaces/api"
 	"github.com/marcopaulosilva/poc_devin/internal/interfaces/db"
 )
 
+const usage = "usage: consumer [flags] [migrate [up | down [steps] | status]]"
+
 func main() {
-	log := logger.NewConsoleLogger()
-	log.Info("Starting champion movement speed consumer application")
-
-	apiBaseURL := getEnvOrDefault("API_BASE_URL", "http://movement-speed-api")
-	dbHost := getEnvOrDefault("DB_HOST", "localhost")
-	dbPort := getEnvOrDefault("DB_PORT", "5432")
-	dbUser := getEnvOrDefault("DB_USER", "postgres")
-	dbPassword := getEnvOrDefault("DB_PASSWORD", "postgres")
-	dbName := getEnvOrDefault("DB_NAME", "champions")
-	dbSSLMode := getEnvOrDefault("DB_SSL_MODE", "disable")
-	syncInterval := getEnvOrDefault("SYNC_INTERVAL", "60") // seconds
-
-	interval, err := time.ParseDuration(syncInterval + "s")
+	cfg, args, err := config.LoadConsumer(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	log := cfg.Log.NewLogger().With("service", "consumer")
 	if err != nil {
-		log.Error("Invalid sync interval: %v", err)
+		log.Error("%v", err)
 		os.Exit(1)
 	}
 
-	dbConfig := dbInfra.PostgresConfig{
-		Host:     dbHost,
-		Port:     parsePort(dbPort),
-		User:     dbUser,
-		Password: dbPassword,
-		DBName:   dbName,
-		SSLMode:  dbSSLMode,
+	migrateMode, err := parseCommand(args)
+	if err != nil {
+		log.Error("%v", err)
+		os.Exit(1)
 	}
+	if !migrateMode {
+		log.Info("Starting champion movement speed consumer application")
+	}
+
+	// A signal cancels ctx, which stops waiting for the database as well as
+	// the sync loop.
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
 
-	dbConn, err := dbInfra.NewPostgresConnection(dbConfig, log)
+	dbConn, err := dbInfra.NewPostgresConnection(ctx, cfg.Database.PostgresConfig(), log)
 	if err != nil {
 		log.Error("Failed to connect to database: %v", err)
 		os.Exit(1)
 	}
 	defer dbConn.Close()
 
-	if err := dbInfra.InitializeDatabase(dbConn, log); err != nil {
+	if migrateMode {
+		if err := runMigrate(ctx, dbConn, args[1:], log); err != nil {
+			log.Error("Migration failed: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := dbInfra.InitializeDatabase(ctx, dbConn, log); err != nil {
 		log.Error("Failed to initialize database: %v", err)
 		os.Exit(1)
 	}
 
-	httpClient := client.NewHTTPClient(10 * time.Second)
+	tracer, err := tracing.New("champion-consumer", cfg.Tracing.Exporter, cfg.Tracing.File)
+	if err != nil {
+		log.Error("Failed to set up tracing: %v", err)
+		os.Exit(1)
+	}
 
-	apiClient := api.NewMovementSpeedClient(httpClient, apiBaseURL, log)
+	registry := metrics.NewRegistry()
+	metrics.RegisterDBStats(registry, dbConn)
+	syncMetrics := newSyncMetrics(registry)
 
-	championRepo := db.NewPostgresChampionRepository(dbConn, log)
+	breakers := client.NewCircuitBreakers(client.DefaultBreakerConfig(), log)
+	httpClient := client.NewHTTPClient(10*time.Second,
+		client.WithRetryPolicy(client.DefaultRetryPolicy()),
+		client.WithCircuitBreakers(breakers),
+		client.WithRequestObserver(metrics.NewClientMetrics(registry)),
+		client.WithTracer(tracer),
+	)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	apiClient := api.NewMovementSpeedClient(httpClient, cfg.APIBaseURL, cfg.Locale, log)
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	championRepo := db.NewPostgresChampionRepository(dbConn, log)
+
+	// A sync may fail twice in a row before the consumer is reported
+	// unready. The API being down degrades it without failing readiness,
+	// since the failed syncs already show up in the sync age.
+	readiness := health.NewProbe(health.DefaultTimeout,
+		health.Check{Name: "database", Checker: health.PingChecker(dbConn), Critical: true},
+		health.Check{Name: "last_sync", Checker: health.MaxAgeChecker(syncMetrics.LastSuccess, 3*cfg.SyncInterval), Critical: true},
+		health.Check{Name: "upstream", Checker: health.HTTPChecker(&http.Client{}, cfg.APIBaseURL+"/livez")},
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry.Handler())
+	mux.Handle("/livez", health.NewProbe(health.DefaultTimeout).Handler())
+	mux.Handle("/readyz", readiness.Handler())
+	server := api.NewServer(cfg.MetricsPort, mux, log)
+	go func() {
+		log.Info("Serving metrics and probes on port %d", cfg.MetricsPort)
+		if err := server.Start(); err != nil && err != http.ErrServerClosed {
+			log.Error("Metrics server failed: %v", err)
+		}
+	}()
 
-	go syncChampionsData(ctx, apiClient, championRepo, interval, log)
+	go syncChampionsData(ctx, apiClient, championRepo, syncMetrics, tracer, cfg.SyncInterval, log)
 
-	<-stop
+	<-ctx.Done()
 	log.Info("Shutting down...")
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Error("Metrics server shutdown failed: %v", err)
+	}
+	if err := tracer.Shutdown(shutdownCtx); err != nil {
+		log.Error("Failed to export traces: %v", err)
+	}
+}
+
+// parseCommand reports whether args, the arguments left after the flags, run
+// the migrate subcommand rather than the sync loop.
+func parseCommand(args []string) (migrate bool, err error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+	if args[0] != "migrate" {
+		return false, fmt.Errorf("unknown command %q, %s", args[0], usage)
+	}
+	return true, nil
 }
 
 func syncChampionsData(
 	ctx context.Context,
 	apiClient *api.MovementSpeedClient,
 	repo repositories.ChampionRepository,
+	syncMetrics *syncMetrics,
+	tracer *tracing.Tracer,
 	interval time.Duration,
 	log logger.Logger,
 ) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	if err := performSync(ctx, apiClient, repo, log); err != nil {
-		log.Error("Initial sync failed: %v", err)
+	if err := performSync(ctx, apiClient, repo, syncMetrics, tracer, log); err != nil {
+		logSyncFailure(log, "Initial sync", err)
 	}
 
 	for {
 		select {
 		case <-ticker.C:
-			if err := performSync(ctx, apiClient, repo, log); err != nil {
-				log.Error("Sync failed: %v", err)
+			if err := performSync(ctx, apiClient, repo, syncMetrics, tracer, log); err != nil {
+				logSyncFailure(log, "Sync", err)
 			}
 		case <-ctx.Done():
 			log.Info("Sync process terminated")
@@ -105,36 +173,47 @@ func performSync(
 	ctx context.Context,
 	apiClient *api.MovementSpeedClient,
 	repo repositories.ChampionRepository,
+	syncMetrics *syncMetrics,
+	tracer *tracing.Tracer,
 	log logger.Logger,
-) error {
+) (err error) {
 	log.Info("Syncing champion data from API to database")
+	start := time.Now()
+
+	ctx, span := tracer.Start(ctx, "champion sync")
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
 
 	champions, err := apiClient.GetChampionsByMovementSpeed(ctx)
-	if err != nil {
+	if errors.Is(err, api.ErrNotModified) {
+		log.Info("Champion data unchanged, skipping database write")
+		syncMetrics.observe(start, syncNotModified)
+		return nil
+	} else if err != nil {
+		syncMetrics.observe(start, syncFailed)
 		return err
 	}
 
 	if err := repo.SaveChampions(ctx, champions); err != nil {
+		apiClient.ResetETag()
+		syncMetrics.observe(start, syncFailed)
 		return err
 	}
 
+	syncMetrics.upserted.Add(float64(len(champions)))
+	syncMetrics.observe(start, syncSucceeded)
 	log.Success("Successfully synced %d champions to database", len(champions))
 	return nil
 }
 
-func getEnvOrDefault(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value
-}
-
-func parsePort(port string) int {
-	var p int
-	_, err := fmt.Sscanf(port, "%d", &p)
-	if err != nil || p <= 0 || p > 65535 {
-		return 5432 // Default PostgreSQL port
+// logSyncFailure tells an unavailable upstream API, whose circuit breaker is
+// open, apart from other sync failures.
+func logSyncFailure(log logger.Logger, name string, err error) {
+	if errors.Is(err, client.ErrCircuitOpen) {
+		log.Warn("%s skipped, upstream API unavailable: %v", name, err)
+		return
 	}
-	return p
+	log.Error("%s failed: %v", name, err)
 }
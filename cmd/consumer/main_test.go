@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/repositories"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/metrics"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/tracing"
+	"github.com/marcopaulosilva/poc_devin/internal/interfaces/api"
+)
+
+// savingRepository records the champions of every SaveChampions call.
+type savingRepository struct {
+	repositories.ChampionRepository
+	saves [][]entities.ChampionRecord
+}
+
+func (r *savingRepository) SaveChampions(ctx context.Context, champions []entities.ChampionRecord) error {
+	r.saves = append(r.saves, champions)
+	return nil
+}
+
+func TestPerformSyncSkipsWriteWhenNotModified(t *testing.T) {
+	const etag = `"v1"`
+	var conditional []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditional = append(conditional, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"locale":"en_US","count":1,"champions":[{"rank":1,"id":"Ahri","name":"Ahri","movementSpeed":330}]}`))
+	}))
+	defer server.Close()
+
+	log := logger.NewJSONLogger(io.Discard, logger.LevelError)
+	apiClient := api.NewMovementSpeedClient(client.NewHTTPClient(5*time.Second), server.URL, "", log)
+	repo := &savingRepository{}
+	syncMetrics := newSyncMetrics(metrics.NewRegistry())
+	tracer := tracing.NewTracer("test", nil)
+
+	for i := 0; i < 2; i++ {
+		if err := performSync(context.Background(), apiClient, repo, syncMetrics, tracer, log); err != nil {
+			t.Fatalf("sync %d: %v", i, err)
+		}
+	}
+
+	if len(conditional) != 2 || conditional[0] != "" || conditional[1] != etag {
+		t.Errorf("sent If-None-Match %q, want none and then %s", conditional, etag)
+	}
+	if len(repo.saves) != 1 || len(repo.saves[0]) != 1 || repo.saves[0][0].ChampionID != "Ahri" {
+		t.Errorf("saved %+v, want Ahri once", repo.saves)
+	}
+	if syncMetrics.LastSuccess().IsZero() {
+		t.Error("an unchanged sync is not recorded as successful")
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		args        []string
+		wantMigrate bool
+		wantErr     bool
+	}{
+		{args: nil},
+		{args: []string{"migrate"}, wantMigrate: true},
+		{args: []string{"migrate", "down", "2"}, wantMigrate: true},
+		{args: []string{"migrte", "up"}, wantErr: true},
+		{args: []string{"sync"}, wantErr: true},
+		{args: []string{"-db-host", "db"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.args), func(t *testing.T) {
+			migrate, err := parseCommand(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error: %t", err, tt.wantErr)
+			}
+			if migrate != tt.wantMigrate {
+				t.Errorf("migrate %t, want %t", migrate, tt.wantMigrate)
+			}
+		})
+	}
+}
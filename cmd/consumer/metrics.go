@@ -0,0 +1,66 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/metrics"
+)
+
+// Sync results recorded by syncMetrics.
+const (
+	syncSucceeded   = "success"
+	syncNotModified = "not_modified"
+	syncFailed      = "failure"
+)
+
+type syncMetrics struct {
+	duration *metrics.HistogramVec
+	syncs    *metrics.CounterVec
+	upserted *metrics.CounterVec
+
+	mu          sync.Mutex
+	lastSuccess time.Time
+}
+
+func newSyncMetrics(registry *metrics.Registry) *syncMetrics {
+	m := &syncMetrics{
+		duration: registry.NewHistogramVec("champion_sync_duration_seconds",
+			"Duration of champion syncs from the API to the database, by result.",
+			[]float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}, "result"),
+		syncs: registry.NewCounterVec("champion_syncs_total",
+			"Number of champion syncs, by result: success, not_modified or failure.",
+			"result"),
+		upserted: registry.NewCounterVec("champions_upserted_total",
+			"Number of champion rows written to the database."),
+	}
+	registry.NewGaugeFunc("champion_last_successful_sync_timestamp_seconds",
+		"Unix time of the last sync that succeeded or found the data unchanged, 0 before the first one.",
+		func() float64 {
+			last := m.LastSuccess()
+			if last.IsZero() {
+				return 0
+			}
+			return float64(last.UnixNano()) / 1e9
+		})
+	return m
+}
+
+func (m *syncMetrics) observe(start time.Time, result string) {
+	m.duration.ObserveDuration(time.Since(start), result)
+	m.syncs.Inc(result)
+
+	if result != syncFailed {
+		m.mu.Lock()
+		m.lastSuccess = time.Now()
+		m.mu.Unlock()
+	}
+}
+
+// LastSuccess returns when the last sync that did not fail finished, or the
+// zero time before the first one.
+func (m *syncMetrics) LastSuccess() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastSuccess
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	dbInfra "github.com/marcopaulosilva/poc_devin/internal/infrastructure/db"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+const migrateUsage = "usage: consumer migrate [up | down [steps] | status]"
+
+// runMigrate implements the "migrate" subcommand:
+//
+//	consumer migrate up          apply every pending migration (default)
+//	consumer migrate down [n]    revert the last n migrations (default 1)
+//	consumer migrate status      list migrations and when they were applied
+func runMigrate(ctx context.Context, dbConn *sql.DB, args []string, log logger.Logger) error {
+	migrator, err := dbInfra.NewMigrator(dbConn, log)
+	if err != nil {
+		return err
+	}
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		if len(args) > 1 {
+			return errors.New(migrateUsage)
+		}
+		return migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(args) > 2 {
+			return errors.New(migrateUsage)
+		}
+		if len(args) == 2 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		return migrator.Down(ctx, steps)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("\n  %-8s %-35s %s\n", "Version", "Name", "Applied At")
+		fmt.Printf("  %-8s %-35s %s\n", "-------", "-----------------------------------", "-------------------")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("  %-8d %-35s %s\n", status.Version, status.Name, appliedAt)
+		}
+		fmt.Println()
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q; %s", command, migrateUsage)
+	}
+}
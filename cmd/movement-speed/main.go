@@ -2,55 +2,74 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"sort"
+	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/marcopaulosilva/poc_devin/internal/config"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
 	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
 	httpRepo "github.com/marcopaulosilva/poc_devin/internal/interfaces/http"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
-	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
 )
 
 func main() {
-	log := logger.NewConsoleLogger()
-	httpClient := client.NewHTTPClient(10 * time.Second)
-	
-	apiKey := os.Getenv("RIOT_API_KEY")
-	if apiKey == "" {
-		log.Error("RIOT_API_KEY environment variable not set")
+	cfg, err := config.LoadMovementSpeed(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	log := cfg.Log.NewLogger().With("service", "movement-speed")
+	if err != nil {
+		log.Error("%v", err)
 		os.Exit(1)
 	}
+	httpClient := client.NewHTTPClient(10*time.Second, client.WithRetryPolicy(client.DefaultRetryPolicy()))
 	
-	baseURL := "https://na1.api.riotgames.com"
-	
-	championRepo := httpRepo.NewChampionRepository(httpClient, log, baseURL, apiKey)
+	championRepo := httpRepo.NewChampionRepository(httpClient, log, httpRepo.ChampionRepositoryConfig{
+		Version: cfg.DataDragon.Version,
+		Workers: cfg.DataDragon.Workers,
+		Mode:    httpRepo.FetchMode(cfg.DataDragon.Mode),
+	})
 	
 	championUseCase := usecases.NewChampionUseCase(championRepo)
 	
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	
 	log.Info("Fetching all League of Legends champions with movement speed data")
-	champions, err := championUseCase.GetAllChampions(ctx)
+	champions, err := championUseCase.RankChampions(ctx, usecases.RankOptions{
+		Stat:    entities.StatMoveSpeed,
+		Order:   entities.SortDescending,
+		Ranking: entities.RankingCompetition,
+	})
 	if err != nil {
 		log.Error("Failed to get champions: %v", err)
 		os.Exit(1)
 	}
 	
-	sort.Slice(champions, func(i, j int) bool {
-		return champions[i].MovementSpeed > champions[j].MovementSpeed
-	})
-	
 	fmt.Printf("\nLeague of Legends Champions Movement Speed (%d):\n\n", len(champions))
 	
-	fmt.Printf("  %-4s %-15s %-25s %s\n", "Rank", "Name", "Title", "Movement Speed")
-	fmt.Printf("  %-4s %-15s %-25s %s\n", "----", "---------------", "-------------------------", "-------------")
+	fmt.Printf("  %-4s %-15s %-25s %-5s %-6s %-5s %-5s %-5s %-6s %s\n",
+		"Rank", "Name", "Title", "MS", "HP", "Armor", "MR", "AD", "AS", "Range")
+	fmt.Printf("  %-4s %-15s %-25s %-5s %-6s %-5s %-5s %-5s %-6s %s\n",
+		"----", "---------------", "-------------------------", "-----", "------", "-----", "-----", "-----", "------", "-----")
 	
-	for i, champion := range champions {
-		fmt.Printf("  %-4d %-15s %-25s %.0f\n", i+1, champion.Name, champion.Title, champion.MovementSpeed)
+	for _, ranked := range champions {
+		champion := ranked.Champion
+		stats := champion.Stats
+		fmt.Printf("  %-4d %-15s %-25s %-5.0f %-6.0f %-5.0f %-5.0f %-5.0f %-6.3f %.0f\n",
+			ranked.Rank, champion.Name, champion.Title, champion.MovementSpeed,
+			stats.HP, stats.Armor, stats.SpellBlock, stats.AttackDamage, stats.AttackSpeed, stats.AttackRange)
 	}
 	
-	fmt.Println("\nChampion movement speed data retrieved from Riot Games Data Dragon API")
+	version := "unknown"
+	if len(champions) > 0 {
+		version = champions[0].Champion.Version
+	}
+	fmt.Printf("\nChampion stats data retrieved from Riot Games Data Dragon API (patch %s)\n", version)
 	log.Success("Movement speed microservice completed successfully")
 }
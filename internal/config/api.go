@@ -0,0 +1,69 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// APIConfig configures the REST API server.
+type APIConfig struct {
+	Port            int              `yaml:"port"`
+	RiotAPIKey      Secret           `yaml:"riotApiKey"`
+	RiotURLTemplate string           `yaml:"riotUrlTemplate"`
+	DataDragon      DataDragonConfig `yaml:"dataDragon"`
+	CacheTTL        time.Duration    `yaml:"cacheTtl"`
+	RequestTimeout  time.Duration    `yaml:"requestTimeout"`
+	// RouteTimeouts overrides RequestTimeout per route, in the format read
+	// by api.ParseRouteTimeouts, which checks it when the server starts.
+	RouteTimeouts string        `yaml:"routeTimeouts"`
+	Log           LogConfig     `yaml:"log"`
+	Tracing       TracingConfig `yaml:"tracing"`
+}
+
+func DefaultAPIConfig() APIConfig {
+	return APIConfig{
+		Port:            8080,
+		RiotURLTemplate: "https://{route}.api.riotgames.com",
+		DataDragon:      defaultDataDragonConfig(),
+		CacheTTL:        10 * time.Minute,
+		RequestTimeout:  10 * time.Second,
+		Log:             defaultLogConfig(),
+		Tracing:         defaultTracingConfig(),
+	}
+}
+
+// LoadAPI loads the API configuration from args, the command-line arguments
+// without the program name, and the environment. See the package
+// documentation for the precedence of each source.
+func LoadAPI(args []string) (APIConfig, error) {
+	cfg := DefaultAPIConfig()
+
+	l := newLoader("api")
+	l.Int(&cfg.Port, "PORT", "port", "port to listen on")
+	l.Secret(&cfg.RiotAPIKey, "RIOT_API_KEY", "riot-api-key", "Riot API key")
+	l.String(&cfg.RiotURLTemplate, "RIOT_API_URL_TEMPLATE", "riot-api-url-template", "Riot API host template with a {route} placeholder")
+	cfg.DataDragon.bind(l)
+	l.Duration(&cfg.CacheTTL, "CACHE_TTL", "cache-ttl", "how long champion data is cached")
+	l.Duration(&cfg.RequestTimeout, "REQUEST_TIMEOUT", "request-timeout", "default request timeout")
+	l.String(&cfg.RouteTimeouts, "ROUTE_TIMEOUTS", "route-timeouts", "comma separated route=duration timeout overrides")
+	cfg.Log.bind(l)
+	cfg.Tracing.bind(l)
+
+	_, err := l.load(args, &cfg)
+	return cfg, err
+}
+
+func (c *APIConfig) validate(p *problems) {
+	p.port("PORT", c.Port)
+	if c.RiotAPIKey == "" {
+		p.add("RIOT_API_KEY is required")
+	}
+	if !strings.Contains(c.RiotURLTemplate, "{route}") {
+		p.add("RIOT_API_URL_TEMPLATE must contain {route}, got %q", c.RiotURLTemplate)
+	}
+	c.DataDragon.validate(p)
+	p.duration("CACHE_TTL", c.CacheTTL)
+	p.duration("REQUEST_TIMEOUT", c.RequestTimeout)
+	c.Log.validate(p)
+	c.Tracing.validate(p)
+}
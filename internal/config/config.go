@@ -0,0 +1,328 @@
+// Package config loads the typed configuration of every binary. Each value
+// comes from, in increasing order of precedence, its default, the optional
+// YAML file named by -config or CONFIG_FILE, its environment variable and
+// its command-line flag. An environment variable that is set but empty still
+// takes precedence, clearing the value. Every value is validated up front and
+// all problems are reported together.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/tracing"
+)
+
+// Secret is a string that is redacted when printed or marshalled.
+type Secret string
+
+const redacted = "[REDACTED]"
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) MarshalYAML() (interface{}, error) {
+	return s.String(), nil
+}
+
+// ValidationError lists every problem found while loading a configuration.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+type problems []string
+
+func (p *problems) add(format string, args ...interface{}) {
+	*p = append(*p, fmt.Sprintf(format, args...))
+}
+
+func (p *problems) port(name string, port int) {
+	if port <= 0 || port > 65535 {
+		p.add("%s must be between 1 and 65535, got %d", name, port)
+	}
+}
+
+// duration rejects durations under a second, which in a YAML file usually
+// come from a bare number read as nanoseconds instead of e.g. "60s".
+func (p *problems) duration(name string, d time.Duration) {
+	if d < time.Second {
+		p.add("%s must be at least 1s, got %s", name, d)
+	}
+}
+
+func (p *problems) url(name, value string) {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		p.add("%s must be an absolute URL, got %q", name, value)
+	}
+}
+
+// LogConfig configures logger.New.
+type LogConfig struct {
+	Format string `yaml:"format"`
+	Level  string `yaml:"level"`
+}
+
+func (c *LogConfig) bind(l *loader) {
+	l.String(&c.Format, "LOG_FORMAT", "log-format", "log format, console or json")
+	l.String(&c.Level, "LOG_LEVEL", "log-level", "minimum log level, debug, info, warn or error")
+}
+
+func (c LogConfig) validate(p *problems) {
+	switch strings.ToLower(c.Format) {
+	case "", "console", "json":
+	default:
+		p.add("LOG_FORMAT must be console or json, got %q", c.Format)
+	}
+	if _, err := logger.ParseLevel(c.Level); err != nil {
+		p.add("LOG_LEVEL must be debug, info, warn or error, got %q", c.Level)
+	}
+}
+
+func defaultLogConfig() LogConfig {
+	return LogConfig{Format: "console", Level: "info"}
+}
+
+// NewLogger builds the configured logger. It falls back to a console logger
+// on invalid values, which Load has already reported.
+func (c LogConfig) NewLogger() logger.Logger {
+	log, _ := logger.New(c.Format, c.Level)
+	return log
+}
+
+// TracingConfig configures tracing.New.
+type TracingConfig struct {
+	Exporter string `yaml:"exporter"`
+	File     string `yaml:"file"`
+}
+
+func (c *TracingConfig) bind(l *loader) {
+	l.String(&c.Exporter, "TRACING_EXPORTER", "tracing-exporter", "span exporter, none, stdout or otlp-file")
+	l.String(&c.File, "TRACING_FILE", "tracing-file", "file the otlp-file exporter appends to")
+}
+
+func (c TracingConfig) validate(p *problems) {
+	switch strings.ToLower(c.Exporter) {
+	case "", "none", "stdout", "otlp-file":
+	default:
+		p.add("TRACING_EXPORTER must be none, stdout or otlp-file, got %q", c.Exporter)
+	}
+}
+
+func defaultTracingConfig() TracingConfig {
+	return TracingConfig{Exporter: "none", File: tracing.DefaultFile}
+}
+
+// DataDragonConfig configures the Data Dragon champion repository.
+type DataDragonConfig struct {
+	// Version pins the patch. Empty resolves the latest one.
+	Version string `yaml:"version"`
+	Workers int    `yaml:"workers"`
+	// Mode is the fetch mode, "detail" or "full". Empty means "detail".
+	Mode string `yaml:"mode"`
+}
+
+func (c *DataDragonConfig) bind(l *loader) {
+	l.String(&c.Version, "DDRAGON_VERSION", "ddragon-version", "Data Dragon patch to pin, latest when empty")
+	l.Int(&c.Workers, "DDRAGON_WORKERS", "ddragon-workers", "concurrent champion detail requests")
+	l.String(&c.Mode, "DDRAGON_MODE", "ddragon-mode", "Data Dragon fetch mode, detail or full")
+}
+
+func (c DataDragonConfig) validate(p *problems) {
+	if c.Workers <= 0 {
+		p.add("DDRAGON_WORKERS must be positive, got %d", c.Workers)
+	}
+	switch c.Mode {
+	case "", "detail", "full":
+	default:
+		p.add("DDRAGON_MODE must be detail or full, got %q", c.Mode)
+	}
+}
+
+func defaultDataDragonConfig() DataDragonConfig {
+	return DataDragonConfig{Workers: 8, Mode: "detail"}
+}
+
+// binding ties a configuration value to its environment variable and flag.
+type binding struct {
+	env       string
+	flag      string
+	set       func(value string) error
+	flagValue *string
+}
+
+// loader collects the bindings of a configuration, then applies the YAML
+// file, the environment and the flags in that order.
+type loader struct {
+	flags    *flag.FlagSet
+	bindings []*binding
+}
+
+func newLoader(name string) *loader {
+	return &loader{flags: flag.NewFlagSet(name, flag.ContinueOnError)}
+}
+
+// bind registers a value parsed by set. Flags only record their value
+// during parsing so that they can be applied after the file and the
+// environment.
+func (l *loader) bind(env, flagName, usage string, set func(value string) error) {
+	b := &binding{env: env, flag: flagName, set: set}
+	l.flags.Func(flagName, fmt.Sprintf("%s (env %s)", usage, env), func(value string) error {
+		b.flagValue = &value
+		return nil
+	})
+	l.bindings = append(l.bindings, b)
+}
+
+func (l *loader) String(target *string, env, flagName, usage string) {
+	l.bind(env, flagName, usage, func(value string) error {
+		*target = value
+		return nil
+	})
+}
+
+func (l *loader) Secret(target *Secret, env, flagName, usage string) {
+	l.bind(env, flagName, usage, func(value string) error {
+		*target = Secret(value)
+		return nil
+	})
+}
+
+// Int reads an empty value as 0.
+func (l *loader) Int(target *int, env, flagName, usage string) {
+	l.bind(env, flagName, usage, func(value string) error {
+		if value == "" {
+			*target = 0
+			return nil
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("not an integer: %q", value)
+		}
+		*target = parsed
+		return nil
+	})
+}
+
+// Duration accepts a Go duration such as "90s", or a number of seconds as
+// the environment variables have always taken. It reads an empty value as 0.
+func (l *loader) Duration(target *time.Duration, env, flagName, usage string) {
+	l.bind(env, flagName, usage, func(value string) error {
+		if value == "" {
+			*target = 0
+			return nil
+		}
+		if seconds, err := strconv.Atoi(value); err == nil {
+			*target = time.Duration(seconds) * time.Second
+			return nil
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("not a duration or a number of seconds: %q", value)
+		}
+		*target = parsed
+		return nil
+	})
+}
+
+type validatable interface {
+	validate(p *problems)
+}
+
+// load fills cfg, which holds the defaults, from args and the environment
+// and validates it. It returns the arguments left after the flags. With -h
+// or -print-config it returns flag.ErrHelp once the usage or the redacted
+// configuration is printed, after which the binary should exit
+// successfully.
+func (l *loader) load(args []string, cfg validatable) ([]string, error) {
+	configFile := l.flags.String("config", "", "YAML configuration file (env CONFIG_FILE)")
+	printConfig := l.flags.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	if err := l.flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var p problems
+	path := *configFile
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := readFile(path, cfg); err != nil {
+			p.add("%v", err)
+		}
+	}
+
+	for _, b := range l.bindings {
+		if value, ok := os.LookupEnv(b.env); ok {
+			if err := b.set(value); err != nil {
+				p.add("%s: %v", b.env, err)
+			}
+		}
+		if b.flagValue != nil {
+			if err := b.set(*b.flagValue); err != nil {
+				p.add("-%s: %v", b.flag, err)
+			}
+		}
+	}
+
+	cfg.validate(&p)
+
+	if *printConfig {
+		if err := Print(os.Stdout, cfg); err != nil {
+			p.add("failed to print configuration: %v", err)
+		}
+		if len(p) == 0 {
+			return nil, flag.ErrHelp
+		}
+	}
+
+	if len(p) > 0 {
+		return nil, &ValidationError{Problems: p}
+	}
+	return l.flags.Args(), nil
+}
+
+// readFile decodes the YAML file at path over cfg. Keys that match no
+// configuration value are rejected so that typos do not go unnoticed.
+func readFile(path string, cfg interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Print writes cfg as YAML with every Secret redacted.
+func Print(w io.Writer, cfg interface{}) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(cfg); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
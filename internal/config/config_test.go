@@ -0,0 +1,215 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"go/build"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var apiEnv = []string{
+	"CONFIG_FILE", "PORT", "RIOT_API_KEY", "RIOT_API_URL_TEMPLATE",
+	"DDRAGON_VERSION", "DDRAGON_WORKERS", "DDRAGON_MODE",
+	"CACHE_TTL", "REQUEST_TIMEOUT", "ROUTE_TIMEOUTS",
+	"LOG_FORMAT", "LOG_LEVEL", "TRACING_EXPORTER", "TRACING_FILE",
+}
+
+// setenv unsets every variable LoadAPI reads for the duration of the test,
+// then sets env.
+func setenv(t *testing.T, env map[string]string) {
+	t.Helper()
+
+	for _, key := range apiEnv {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAPIPrecedence(t *testing.T) {
+	file := writeFile(t, "port: 8081\ncacheTtl: 2m\n")
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		args     []string
+		wantPort int
+		wantTTL  time.Duration
+	}{
+		{name: "default", wantPort: 8080, wantTTL: DefaultAPIConfig().CacheTTL},
+		{name: "file from flag", args: []string{"-config", file}, wantPort: 8081, wantTTL: 2 * time.Minute},
+		{name: "file from environment", env: map[string]string{"CONFIG_FILE": file}, wantPort: 8081, wantTTL: 2 * time.Minute},
+		{
+			name:     "environment over file",
+			env:      map[string]string{"CONFIG_FILE": file, "PORT": "8082"},
+			wantPort: 8082, wantTTL: 2 * time.Minute,
+		},
+		{
+			name:     "flag over environment",
+			env:      map[string]string{"CONFIG_FILE": file, "PORT": "8082", "CACHE_TTL": "180"},
+			args:     []string{"-port", "8083"},
+			wantPort: 8083, wantTTL: 3 * time.Minute,
+		},
+		{
+			name:     "flag over file",
+			args:     []string{"-config", file, "-cache-ttl", "4m"},
+			wantPort: 8081, wantTTL: 4 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := map[string]string{"RIOT_API_KEY": "key"}
+			for key, value := range tt.env {
+				env[key] = value
+			}
+			setenv(t, env)
+
+			cfg, err := LoadAPI(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Port != tt.wantPort || cfg.CacheTTL != tt.wantTTL {
+				t.Errorf("port %d and cache TTL %s, want %d and %s", cfg.Port, cfg.CacheTTL, tt.wantPort, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestLoadAPIEmptyEnvironmentClearsValue(t *testing.T) {
+	setenv(t, map[string]string{
+		"RIOT_API_KEY":    "key",
+		"CONFIG_FILE":     writeFile(t, "dataDragon:\n  version: 15.7.1\n"),
+		"DDRAGON_VERSION": "",
+		"TRACING_FILE":    "",
+	})
+
+	cfg, err := LoadAPI(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DataDragon.Version != "" {
+		t.Errorf("Data Dragon version %q from the file, want it cleared", cfg.DataDragon.Version)
+	}
+	if cfg.Tracing.File != "" {
+		t.Errorf("tracing file %q from the default, want it cleared", cfg.Tracing.File)
+	}
+}
+
+func TestLoadAPIReportsEveryProblem(t *testing.T) {
+	setenv(t, map[string]string{
+		"PORT":         "0",
+		"CACHE_TTL":    "soon",
+		"LOG_LEVEL":    "loud",
+		"DDRAGON_MODE": "lazy",
+	})
+
+	_, err := LoadAPI([]string{"-request-timeout", "10ms", "-ddragon-workers", ""})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("got error %v, want a ValidationError", err)
+	}
+	want := []string{
+		"CACHE_TTL: not a duration or a number of seconds",
+		"PORT must be between 1 and 65535",
+		"RIOT_API_KEY is required",
+		"DDRAGON_WORKERS must be positive",
+		"DDRAGON_MODE must be detail or full",
+		"REQUEST_TIMEOUT must be at least 1s",
+		"LOG_LEVEL must be debug, info, warn or error",
+	}
+	if len(validationErr.Problems) != len(want) {
+		t.Fatalf("got problems %q, want %d", validationErr.Problems, len(want))
+	}
+	for i, problem := range validationErr.Problems {
+		if !strings.HasPrefix(problem, want[i]) {
+			t.Errorf("problem %d is %q, want it to start with %q", i, problem, want[i])
+		}
+	}
+}
+
+func TestLoadAPIRejectsUnknownFileKeys(t *testing.T) {
+	setenv(t, map[string]string{"RIOT_API_KEY": "key"})
+
+	_, err := LoadAPI([]string{"-config", writeFile(t, "prot: 8081\n")})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Problems) != 1 || !strings.Contains(validationErr.Problems[0], "field prot not found") {
+		t.Errorf("got error %v, want the unknown key reported", err)
+	}
+}
+
+func TestLoadAPIPrintConfigRedactsSecrets(t *testing.T) {
+	setenv(t, map[string]string{"RIOT_API_KEY": "RGAPI-secret"})
+
+	output := captureStdout(t, func() {
+		if _, err := LoadAPI([]string{"-print-config", "-port", "9000"}); !errors.Is(err, flag.ErrHelp) {
+			t.Errorf("got error %v, want %v", err, flag.ErrHelp)
+		}
+	})
+
+	if strings.Contains(output, "RGAPI-secret") {
+		t.Errorf("printed configuration leaks the API key:\n%s", output)
+	}
+	for _, line := range []string{"port: 9000", "riotApiKey: '[REDACTED]'"} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("printed configuration lacks %q:\n%s", line, output)
+		}
+	}
+}
+
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+
+	file, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	stdout := os.Stdout
+	os.Stdout = file
+	defer func() { os.Stdout = stdout }()
+	f()
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	output, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(output)
+}
+
+// TestImportsNothingAboveInfrastructure keeps the configuration free of the
+// interface adapters it configures, which the cmd packages wire up instead.
+func TestImportsNothingAboveInfrastructure(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range pkg.Imports {
+		if strings.Contains(path, "/internal/interfaces/") {
+			t.Errorf("config imports %s", path)
+		}
+	}
+}
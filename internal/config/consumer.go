@@ -0,0 +1,188 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	dbInfra "github.com/marcopaulosilva/poc_devin/internal/infrastructure/db"
+)
+
+// ConsumerConfig configures the consumer that syncs champions from the API
+// into PostgreSQL.
+type ConsumerConfig struct {
+	APIBaseURL   string         `yaml:"apiBaseUrl"`
+	Locale       string         `yaml:"locale"`
+	SyncInterval time.Duration  `yaml:"syncInterval"`
+	MetricsPort  int            `yaml:"metricsPort"`
+	Database     DatabaseConfig `yaml:"database"`
+	Log          LogConfig      `yaml:"log"`
+	Tracing      TracingConfig  `yaml:"tracing"`
+}
+
+// DatabaseConfig configures the PostgreSQL connection. URL, when set,
+// replaces Host, Port, User, Password, Name and SSLMode.
+type DatabaseConfig struct {
+	URL      Secret `yaml:"url"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password Secret `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslMode"`
+
+	SSLRootCert string `yaml:"sslRootCert"`
+	SSLCert     string `yaml:"sslCert"`
+	SSLKey      string `yaml:"sslKey"`
+
+	MaxOpenConns        int           `yaml:"maxOpenConns"`
+	MaxIdleConns        int           `yaml:"maxIdleConns"`
+	ConnMaxLifetime     time.Duration `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime     time.Duration `yaml:"connMaxIdleTime"`
+	ConnectRetryTimeout time.Duration `yaml:"connectRetryTimeout"`
+}
+
+func (c *DatabaseConfig) bind(l *loader) {
+	l.Secret(&c.URL, "DATABASE_URL", "database-url", "database URL, replacing the other connection settings")
+	l.String(&c.Host, "DB_HOST", "db-host", "database host")
+	l.Int(&c.Port, "DB_PORT", "db-port", "database port")
+	l.String(&c.User, "DB_USER", "db-user", "database user")
+	l.Secret(&c.Password, "DB_PASSWORD", "db-password", "database password")
+	l.String(&c.Name, "DB_NAME", "db-name", "database name")
+	l.String(&c.SSLMode, "DB_SSL_MODE", "db-ssl-mode", "libpq sslmode, e.g. disable or verify-full")
+	l.String(&c.SSLRootCert, "DB_SSL_ROOT_CERT", "db-ssl-root-cert", "CA certificate file to verify the server with")
+	l.String(&c.SSLCert, "DB_SSL_CERT", "db-ssl-cert", "client certificate file")
+	l.String(&c.SSLKey, "DB_SSL_KEY", "db-ssl-key", "client certificate key file")
+	l.Int(&c.MaxOpenConns, "DB_MAX_OPEN_CONNS", "db-max-open-conns", "maximum number of open connections")
+	l.Int(&c.MaxIdleConns, "DB_MAX_IDLE_CONNS", "db-max-idle-conns", "maximum number of idle connections")
+	l.Duration(&c.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME", "db-conn-max-lifetime", "maximum lifetime of a connection")
+	l.Duration(&c.ConnMaxIdleTime, "DB_CONN_MAX_IDLE_TIME", "db-conn-max-idle-time", "maximum idle time of a connection, 0 for no limit")
+	l.Duration(&c.ConnectRetryTimeout, "DB_CONNECT_RETRY_TIMEOUT", "db-connect-retry-timeout", "how long to retry the initial connection")
+}
+
+func (c DatabaseConfig) validate(p *problems) {
+	if c.URL == "" {
+		if c.Host == "" {
+			p.add("DB_HOST is required")
+		}
+		p.port("DB_PORT", c.Port)
+		if c.User == "" {
+			p.add("DB_USER is required")
+		}
+		if c.Name == "" {
+			p.add("DB_NAME is required")
+		}
+		switch c.SSLMode {
+		case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		default:
+			p.add("DB_SSL_MODE must be disable, allow, prefer, require, verify-ca or verify-full, got %q", c.SSLMode)
+		}
+	} else if _, err := c.PostgresConfig().DSN(); err != nil {
+		// The error may quote the URL, password included.
+		p.add("DATABASE_URL is not a valid postgres:// URL")
+	}
+
+	for _, file := range []struct{ name, path string }{
+		{"DB_SSL_ROOT_CERT", c.SSLRootCert},
+		{"DB_SSL_CERT", c.SSLCert},
+		{"DB_SSL_KEY", c.SSLKey},
+	} {
+		if file.path == "" {
+			continue
+		}
+		if _, err := os.Stat(file.path); err != nil {
+			p.add("%s: %v", file.name, err)
+		}
+	}
+	if (c.SSLCert == "") != (c.SSLKey == "") {
+		p.add("DB_SSL_CERT and DB_SSL_KEY must be set together")
+	}
+
+	if c.MaxOpenConns <= 0 {
+		p.add("DB_MAX_OPEN_CONNS must be positive, got %d", c.MaxOpenConns)
+	}
+	if c.MaxIdleConns < 0 || c.MaxIdleConns > c.MaxOpenConns {
+		p.add("DB_MAX_IDLE_CONNS must be between 0 and DB_MAX_OPEN_CONNS, got %d", c.MaxIdleConns)
+	}
+	p.duration("DB_CONN_MAX_LIFETIME", c.ConnMaxLifetime)
+	if c.ConnMaxIdleTime != 0 {
+		p.duration("DB_CONN_MAX_IDLE_TIME", c.ConnMaxIdleTime)
+	}
+	p.duration("DB_CONNECT_RETRY_TIMEOUT", c.ConnectRetryTimeout)
+}
+
+// PostgresConfig returns the configuration of the database connection.
+func (c DatabaseConfig) PostgresConfig() dbInfra.PostgresConfig {
+	return dbInfra.PostgresConfig{
+		URL:                 string(c.URL),
+		Host:                c.Host,
+		Port:                c.Port,
+		User:                c.User,
+		Password:            string(c.Password),
+		DBName:              c.Name,
+		SSLMode:             c.SSLMode,
+		SSLRootCert:         c.SSLRootCert,
+		SSLCert:             c.SSLCert,
+		SSLKey:              c.SSLKey,
+		MaxOpenConns:        c.MaxOpenConns,
+		MaxIdleConns:        c.MaxIdleConns,
+		ConnMaxLifetime:     c.ConnMaxLifetime,
+		ConnMaxIdleTime:     c.ConnMaxIdleTime,
+		ConnectRetryTimeout: c.ConnectRetryTimeout,
+	}
+}
+
+func DefaultConsumerConfig() ConsumerConfig {
+	return ConsumerConfig{
+		APIBaseURL:   "http://movement-speed-api",
+		Locale:       entities.DefaultLocale,
+		SyncInterval: 60 * time.Second,
+		MetricsPort:  9090,
+		Database: DatabaseConfig{
+			Host:     "localhost",
+			Port:     5432,
+			User:     "postgres",
+			Password: "postgres",
+			Name:     "champions",
+			SSLMode:  "disable",
+
+			MaxOpenConns:        dbInfra.DefaultMaxOpenConns,
+			MaxIdleConns:        dbInfra.DefaultMaxIdleConns,
+			ConnMaxLifetime:     dbInfra.DefaultConnMaxLifetime,
+			ConnectRetryTimeout: dbInfra.DefaultConnectRetryTimeout,
+		},
+		Log:     defaultLogConfig(),
+		Tracing: defaultTracingConfig(),
+	}
+}
+
+// LoadConsumer loads the consumer configuration like LoadAPI. It also
+// returns the arguments left after the flags, which hold the migrate
+// command.
+func LoadConsumer(args []string) (ConsumerConfig, []string, error) {
+	cfg := DefaultConsumerConfig()
+
+	l := newLoader("consumer")
+	l.String(&cfg.APIBaseURL, "API_BASE_URL", "api-base-url", "base URL of the movement speed API")
+	l.String(&cfg.Locale, "CHAMPION_LOCALE", "locale", "Data Dragon locale of the synced champion names")
+	l.Duration(&cfg.SyncInterval, "SYNC_INTERVAL", "sync-interval", "time between syncs")
+	l.Int(&cfg.MetricsPort, "METRICS_PORT", "metrics-port", "port serving metrics and probes")
+	cfg.Database.bind(l)
+	cfg.Log.bind(l)
+	cfg.Tracing.bind(l)
+
+	rest, err := l.load(args, &cfg)
+	return cfg, rest, err
+}
+
+func (c *ConsumerConfig) validate(p *problems) {
+	p.url("API_BASE_URL", c.APIBaseURL)
+	if c.Locale == "" {
+		p.add("CHAMPION_LOCALE is required")
+	}
+	p.duration("SYNC_INTERVAL", c.SyncInterval)
+	p.port("METRICS_PORT", c.MetricsPort)
+	c.Database.validate(p)
+	c.Log.validate(p)
+	c.Tracing.validate(p)
+}
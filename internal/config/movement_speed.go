@@ -0,0 +1,32 @@
+package config
+
+// MovementSpeedConfig configures the movement speed command-line tool.
+type MovementSpeedConfig struct {
+	DataDragon DataDragonConfig `yaml:"dataDragon"`
+	Log        LogConfig        `yaml:"log"`
+}
+
+func DefaultMovementSpeedConfig() MovementSpeedConfig {
+	return MovementSpeedConfig{
+		DataDragon: defaultDataDragonConfig(),
+		Log:        defaultLogConfig(),
+	}
+}
+
+// LoadMovementSpeed loads the movement speed tool configuration like
+// LoadAPI.
+func LoadMovementSpeed(args []string) (MovementSpeedConfig, error) {
+	cfg := DefaultMovementSpeedConfig()
+
+	l := newLoader("movement-speed")
+	cfg.DataDragon.bind(l)
+	cfg.Log.bind(l)
+
+	_, err := l.load(args, &cfg)
+	return cfg, err
+}
+
+func (c *MovementSpeedConfig) validate(p *problems) {
+	c.DataDragon.validate(p)
+	c.Log.validate(p)
+}
@@ -1,11 +1,38 @@
 package entities
 
 type Champion struct {
-	ID           string  `json:"id"`
-	Key          string  `json:"key"`
-	Name         string  `json:"name"`
-	Title        string  `json:"title"`
-	MovementSpeed float64 `json:"movespeed"`
+	ID            string        `json:"id"`
+	Key           string        `json:"key"`
+	Name          string        `json:"name"`
+	Title         string        `json:"title"`
+	Tags          []string      `json:"tags"`
+	MovementSpeed float64       `json:"movespeed"`
+	Stats         ChampionStats `json:"stats"`
+	Version       string        `json:"version"`
+	// Locale is the Data Dragon locale of Name and Title, e.g. "pt_BR".
+	Locale string `json:"locale"`
+}
+
+// DefaultLocale is the Data Dragon locale used when none is requested.
+const DefaultLocale = "en_US"
+
+// ChampionStats holds the base stats of a champion at level 1 together with
+// the amount each one grows per level, using the Data Dragon field names.
+type ChampionStats struct {
+	HP                   float64 `json:"hp"`
+	HPPerLevel           float64 `json:"hpperlevel"`
+	MP                   float64 `json:"mp"`
+	MPPerLevel           float64 `json:"mpperlevel"`
+	MoveSpeed            float64 `json:"movespeed"`
+	Armor                float64 `json:"armor"`
+	ArmorPerLevel        float64 `json:"armorperlevel"`
+	SpellBlock           float64 `json:"spellblock"`
+	SpellBlockPerLevel   float64 `json:"spellblockperlevel"`
+	AttackRange          float64 `json:"attackrange"`
+	AttackDamage         float64 `json:"attackdamage"`
+	AttackDamagePerLevel float64 `json:"attackdamageperlevel"`
+	AttackSpeed          float64 `json:"attackspeed"`
+	AttackSpeedPerLevel  float64 `json:"attackspeedperlevel"`
 }
 
 type ChampionData struct {
@@ -16,9 +43,10 @@ type ChampionData struct {
 }
 
 type ChampionInfo struct {
-	ID           string  `json:"id"`
-	Key          string  `json:"key"`
-	Name         string  `json:"name"`
-	Title        string  `json:"title"`
-	MovementSpeed float64 `json:"movespeed"`
+	ID            string   `json:"id"`
+	Key           string   `json:"key"`
+	Name          string   `json:"name"`
+	Title         string   `json:"title"`
+	Tags          []string `json:"tags"`
+	MovementSpeed float64  `json:"movespeed"`
 }
@@ -5,11 +5,26 @@ import (
 )
 
 type ChampionRecord struct {
-	ID            int       `json:"id"`
-	ChampionID    string    `json:"champion_id"`
-	Name          string    `json:"name"`
-	Title         string    `json:"title"`
-	MovementSpeed float64   `json:"movement_speed"`
-	Rank          int       `json:"rank"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            int           `json:"id"`
+	ChampionID    string        `json:"champion_id"`
+	Name          string        `json:"name"`
+	Title         string        `json:"title"`
+	Locale        string        `json:"locale"`
+	MovementSpeed float64       `json:"movement_speed"`
+	Stats         ChampionStats `json:"stats"`
+	Rank          int           `json:"rank"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// ChampionStatChange is an entry of the append-only champion history. OldValue
+// is nil only in entries written before the first value of a champion stopped
+// being recorded.
+type ChampionStatChange struct {
+	ID         int       `json:"id"`
+	ChampionID string    `json:"champion_id"`
+	Stat       string    `json:"stat"`
+	OldValue   *float64  `json:"old_value"`
+	NewValue   float64   `json:"new_value"`
+	ChangedAt  time.Time `json:"changed_at"`
 }
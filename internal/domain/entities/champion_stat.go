@@ -0,0 +1,81 @@
+package entities
+
+import "fmt"
+
+const (
+	MinChampionLevel = 1
+	MaxChampionLevel = 18
+)
+
+// Stat names a base stat using its Data Dragon field name.
+type Stat string
+
+const (
+	StatHP           Stat = "hp"
+	StatMP           Stat = "mp"
+	StatMoveSpeed    Stat = "movespeed"
+	StatArmor        Stat = "armor"
+	StatSpellBlock   Stat = "spellblock"
+	StatAttackRange  Stat = "attackrange"
+	StatAttackDamage Stat = "attackdamage"
+	StatAttackSpeed  Stat = "attackspeed"
+)
+
+var Stats = []Stat{
+	StatHP,
+	StatMP,
+	StatMoveSpeed,
+	StatArmor,
+	StatSpellBlock,
+	StatAttackRange,
+	StatAttackDamage,
+	StatAttackSpeed,
+}
+
+func ParseStat(value string) (Stat, error) {
+	for _, stat := range Stats {
+		if string(stat) == value {
+			return stat, nil
+		}
+	}
+	return "", fmt.Errorf("unknown stat %q", value)
+}
+
+// ValueAt returns the value of stat for a champion at the given level using
+// the in-game growth formula. Attack speed growth is a percentage of the
+// base value; movement speed and attack range do not grow.
+func (s ChampionStats) ValueAt(stat Stat, level int) (float64, error) {
+	if level < MinChampionLevel || level > MaxChampionLevel {
+		return 0, fmt.Errorf("level %d is outside %d-%d", level, MinChampionLevel, MaxChampionLevel)
+	}
+
+	switch stat {
+	case StatHP:
+		return grow(s.HP, s.HPPerLevel, level), nil
+	case StatMP:
+		return grow(s.MP, s.MPPerLevel, level), nil
+	case StatMoveSpeed:
+		return s.MoveSpeed, nil
+	case StatArmor:
+		return grow(s.Armor, s.ArmorPerLevel, level), nil
+	case StatSpellBlock:
+		return grow(s.SpellBlock, s.SpellBlockPerLevel, level), nil
+	case StatAttackRange:
+		return s.AttackRange, nil
+	case StatAttackDamage:
+		return grow(s.AttackDamage, s.AttackDamagePerLevel, level), nil
+	case StatAttackSpeed:
+		return s.AttackSpeed * (1 + growthFactor(level)*s.AttackSpeedPerLevel/100), nil
+	default:
+		return 0, fmt.Errorf("unknown stat %q", stat)
+	}
+}
+
+func grow(base, perLevel float64, level int) float64 {
+	return base + perLevel*growthFactor(level)
+}
+
+func growthFactor(level int) float64 {
+	n := float64(level - 1)
+	return n * (0.7025 + 0.0175*n)
+}
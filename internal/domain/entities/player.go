@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Account is a Riot account as returned by the account-v1 API.
+type Account struct {
+	PUUID    string `json:"puuid"`
+	GameName string `json:"gameName"`
+	TagLine  string `json:"tagLine"`
+}
+
+// ParseRiotID splits a Riot ID of the form "GameName#TagLine".
+func ParseRiotID(riotID string) (gameName, tagLine string, err error) {
+	index := strings.LastIndex(riotID, "#")
+	if index <= 0 || index == len(riotID)-1 {
+		return "", "", fmt.Errorf("invalid Riot ID %q, expected GameName#TagLine", riotID)
+	}
+	return riotID[:index], riotID[index+1:], nil
+}
+
+// ChampionMastery is a player's mastery of one champion as returned by the
+// champion-mastery-v4 API. ChampionID is the numeric champion key used by
+// Data Dragon, not the champion's string ID.
+type ChampionMastery struct {
+	PUUID          string `json:"puuid"`
+	ChampionID     int64  `json:"championId"`
+	ChampionLevel  int    `json:"championLevel"`
+	ChampionPoints int64  `json:"championPoints"`
+	LastPlayTime   int64  `json:"lastPlayTime"`
+}
+
+// MasteryChampion joins a mastery entry with the champion and its movement
+// speed rank. Champion is nil when the champion is missing from Data Dragon,
+// e.g. right after a release.
+type MasteryChampion struct {
+	Mastery           ChampionMastery
+	Champion          *Champion
+	MovementSpeedRank int
+}
+
+// PlayerMastery is a player's top mastery champions on a platform, highest
+// points first.
+type PlayerMastery struct {
+	Platform  Platform
+	Account   Account
+	Champions []MasteryChampion
+}
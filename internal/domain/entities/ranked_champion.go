@@ -0,0 +1,25 @@
+package entities
+
+// RankingMethod decides how champions sharing a value are numbered.
+type RankingMethod string
+
+const (
+	// RankingCompetition gives ties the same rank and skips the following
+	// ranks ("1224").
+	RankingCompetition RankingMethod = "competition"
+	// RankingDense gives ties the same rank without gaps ("1223").
+	RankingDense RankingMethod = "dense"
+)
+
+type SortOrder string
+
+const (
+	SortDescending SortOrder = "desc"
+	SortAscending  SortOrder = "asc"
+)
+
+type RankedChampion struct {
+	Rank     int
+	Value    float64
+	Champion Champion
+}
@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Platform is a Riot platform routing value. Platform routed APIs such as
+// champion-mastery-v4 are served by the host of the player's platform.
+type Platform string
+
+const (
+	PlatformBR1  Platform = "br1"
+	PlatformEUN1 Platform = "eun1"
+	PlatformEUW1 Platform = "euw1"
+	PlatformJP1  Platform = "jp1"
+	PlatformKR   Platform = "kr"
+	PlatformLA1  Platform = "la1"
+	PlatformLA2  Platform = "la2"
+	PlatformME1  Platform = "me1"
+	PlatformNA1  Platform = "na1"
+	PlatformOC1  Platform = "oc1"
+	PlatformPH2  Platform = "ph2"
+	PlatformRU   Platform = "ru"
+	PlatformSG2  Platform = "sg2"
+	PlatformTH2  Platform = "th2"
+	PlatformTR1  Platform = "tr1"
+	PlatformTW2  Platform = "tw2"
+	PlatformVN2  Platform = "vn2"
+
+	DefaultPlatform = PlatformNA1
+)
+
+// Region is a Riot regional routing value. Regional routed APIs such as
+// account-v1 are served by the host of the region a platform belongs to.
+type Region string
+
+const (
+	RegionAmericas Region = "americas"
+	RegionAsia     Region = "asia"
+	RegionEurope   Region = "europe"
+	RegionSEA      Region = "sea"
+)
+
+var platformRegions = map[Platform]Region{
+	PlatformBR1:  RegionAmericas,
+	PlatformLA1:  RegionAmericas,
+	PlatformLA2:  RegionAmericas,
+	PlatformNA1:  RegionAmericas,
+	PlatformJP1:  RegionAsia,
+	PlatformKR:   RegionAsia,
+	PlatformEUN1: RegionEurope,
+	PlatformEUW1: RegionEurope,
+	PlatformME1:  RegionEurope,
+	PlatformRU:   RegionEurope,
+	PlatformTR1:  RegionEurope,
+	PlatformOC1:  RegionSEA,
+	PlatformPH2:  RegionSEA,
+	PlatformSG2:  RegionSEA,
+	PlatformTH2:  RegionSEA,
+	PlatformTW2:  RegionSEA,
+	PlatformVN2:  RegionSEA,
+}
+
+// ParsePlatform accepts a platform routing value in any case. An empty value
+// selects DefaultPlatform.
+func ParsePlatform(value string) (Platform, error) {
+	if value == "" {
+		return DefaultPlatform, nil
+	}
+	platform := Platform(strings.ToLower(value))
+	if _, ok := platformRegions[platform]; !ok {
+		return "", fmt.Errorf("unknown region %q", value)
+	}
+	return platform, nil
+}
+
+// Region returns the regional routing value the platform belongs to.
+func (p Platform) Region() Region {
+	return platformRegions[p]
+}
@@ -2,14 +2,19 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
 )
 
 type ChampionRepository interface {
 	SaveChampions(ctx context.Context, champions []entities.ChampionRecord) error
-	
+
 	GetChampions(ctx context.Context) ([]entities.ChampionRecord, error)
-	
+
 	GetChampionByID(ctx context.Context, id string) (*entities.ChampionRecord, error)
+
+	GetChampionHistory(ctx context.Context, championID string) ([]entities.ChampionStatChange, error)
+
+	GetChangesBetween(ctx context.Context, from, to time.Time) ([]entities.ChampionStatChange, error)
 }
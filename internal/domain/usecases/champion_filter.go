@@ -0,0 +1,72 @@
+package usecases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+)
+
+// ChampionFilter selects champions of a ranking. Zero values match every
+// champion.
+type ChampionFilter struct {
+	MinMovementSpeed *float64
+	MaxMovementSpeed *float64
+	// NamePrefix matches the start of the name or ID, ignoring case.
+	NamePrefix string
+	// Tags lists roles such as "Assassin" or "Mage" that a champion must all
+	// have, ignoring case.
+	Tags []string
+}
+
+// Validate reports an inverted movement speed range.
+func (f ChampionFilter) Validate() error {
+	if f.MinMovementSpeed != nil && f.MaxMovementSpeed != nil && *f.MinMovementSpeed > *f.MaxMovementSpeed {
+		return fmt.Errorf("%w: minimum movement speed %g is greater than maximum %g", ErrInvalidParameter, *f.MinMovementSpeed, *f.MaxMovementSpeed)
+	}
+	return nil
+}
+
+func (f ChampionFilter) Match(champion entities.Champion) bool {
+	if f.MinMovementSpeed != nil && champion.MovementSpeed < *f.MinMovementSpeed {
+		return false
+	}
+	if f.MaxMovementSpeed != nil && champion.MovementSpeed > *f.MaxMovementSpeed {
+		return false
+	}
+
+	if f.NamePrefix != "" {
+		prefix := strings.ToLower(f.NamePrefix)
+		if !strings.HasPrefix(strings.ToLower(champion.Name), prefix) && !strings.HasPrefix(strings.ToLower(champion.ID), prefix) {
+			return false
+		}
+	}
+
+	for _, tag := range f.Tags {
+		if !hasTag(champion, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterRanked keeps the champions matching f. Ranks are left untouched, so
+// they stay relative to the full ranking.
+func (f ChampionFilter) FilterRanked(ranked []entities.RankedChampion) []entities.RankedChampion {
+	filtered := make([]entities.RankedChampion, 0, len(ranked))
+	for _, champion := range ranked {
+		if f.Match(champion.Champion) {
+			filtered = append(filtered, champion)
+		}
+	}
+	return filtered
+}
+
+func hasTag(champion entities.Champion, tag string) bool {
+	for _, candidate := range champion.Tags {
+		if strings.EqualFold(candidate, tag) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,20 +2,47 @@ package usecases
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 
 	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
 )
 
 type ChampionUseCase interface {
-	GetAllChampions(ctx context.Context) ([]entities.Champion, error)
+	GetAllChampions(ctx context.Context, locale string) ([]entities.Champion, error)
+	RankChampions(ctx context.Context, options RankOptions) ([]entities.RankedChampion, error)
+	// GetChampion finds a champion by its Data Dragon ID ("Kassadin", in any
+	// case) or numeric key ("38") and ranks it among all champions according
+	// to options. A missing champion is reported with an error wrapping
+	// ErrNotFound.
+	GetChampion(ctx context.Context, idOrKey string, options RankOptions) (*entities.RankedChampion, error)
+	// GetLocales lists the locales champion names and titles are available in.
+	GetLocales(ctx context.Context) ([]string, error)
+}
+
+// RankOptions controls how RankChampions orders champions. Zero values
+// default to descending order, level 1, competition ranking and
+// entities.DefaultLocale.
+type RankOptions struct {
+	Stat    entities.Stat
+	Order   entities.SortOrder
+	Level   int
+	Ranking entities.RankingMethod
+	Locale  string
 }
 
 type ChampionUseCaseImpl struct {
 	championRepo ChampionRepository
 }
 
+// ChampionRepository returns champions with names and titles in the given
+// locale. Locales that are not supported are reported with an error wrapping
+// ErrInvalidParameter.
 type ChampionRepository interface {
-	GetAllChampions(ctx context.Context) ([]entities.Champion, error)
+	GetAllChampions(ctx context.Context, locale string) ([]entities.Champion, error)
+	GetLocales(ctx context.Context) ([]string, error)
 }
 
 func NewChampionUseCase(championRepo ChampionRepository) ChampionUseCase {
@@ -24,6 +51,124 @@ func NewChampionUseCase(championRepo ChampionRepository) ChampionUseCase {
 	}
 }
 
-func (uc *ChampionUseCaseImpl) GetAllChampions(ctx context.Context) ([]entities.Champion, error) {
-	return uc.championRepo.GetAllChampions(ctx)
+func (uc *ChampionUseCaseImpl) GetAllChampions(ctx context.Context, locale string) ([]entities.Champion, error) {
+	if locale == "" {
+		locale = entities.DefaultLocale
+	}
+	return uc.championRepo.GetAllChampions(ctx, locale)
+}
+
+func (uc *ChampionUseCaseImpl) GetLocales(ctx context.Context) ([]string, error) {
+	return uc.championRepo.GetLocales(ctx)
+}
+
+func (uc *ChampionUseCaseImpl) RankChampions(ctx context.Context, options RankOptions) ([]entities.RankedChampion, error) {
+	options, err := options.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	champions, err := uc.championRepo.GetAllChampions(ctx, options.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankChampions(champions, options)
+}
+
+func (uc *ChampionUseCaseImpl) GetChampion(ctx context.Context, idOrKey string, options RankOptions) (*entities.RankedChampion, error) {
+	ranked, err := uc.RankChampions(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, champion := range ranked {
+		if champion.Champion.Key == idOrKey || strings.EqualFold(champion.Champion.ID, idOrKey) {
+			return &champion, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: champion %q", ErrNotFound, idOrKey)
+}
+
+// rankChampions orders champions by the stat value at options.Level and
+// numbers them according to options.Ranking. Ties are ordered by name.
+func rankChampions(champions []entities.Champion, options RankOptions) ([]entities.RankedChampion, error) {
+	ranked := make([]entities.RankedChampion, 0, len(champions))
+	for _, champion := range champions {
+		value, err := champion.Stats.ValueAt(options.Stat, options.Level)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+		ranked = append(ranked, entities.RankedChampion{Value: value, Champion: champion})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if !sameValue(ranked[i].Value, ranked[j].Value) {
+			if options.Order == entities.SortAscending {
+				return ranked[i].Value < ranked[j].Value
+			}
+			return ranked[i].Value > ranked[j].Value
+		}
+		return ranked[i].Champion.Name < ranked[j].Champion.Name
+	})
+
+	for i := range ranked {
+		switch {
+		case i == 0:
+			ranked[i].Rank = 1
+		case sameValue(ranked[i].Value, ranked[i-1].Value):
+			ranked[i].Rank = ranked[i-1].Rank
+		case options.Ranking == entities.RankingDense:
+			ranked[i].Rank = ranked[i-1].Rank + 1
+		default:
+			ranked[i].Rank = i + 1
+		}
+	}
+
+	return ranked, nil
+}
+
+// Normalize fills in defaults and validates the options.
+func (o RankOptions) Normalize() (RankOptions, error) {
+	if o.Stat == "" {
+		o.Stat = entities.StatMoveSpeed
+	}
+	if _, err := entities.ParseStat(string(o.Stat)); err != nil {
+		return o, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	switch o.Order {
+	case "":
+		o.Order = entities.SortDescending
+	case entities.SortAscending, entities.SortDescending:
+	default:
+		return o, fmt.Errorf("%w: unknown sort order %q", ErrInvalidParameter, o.Order)
+	}
+
+	if o.Level == 0 {
+		o.Level = entities.MinChampionLevel
+	}
+	if o.Level < entities.MinChampionLevel || o.Level > entities.MaxChampionLevel {
+		return o, fmt.Errorf("%w: level must be between %d and %d", ErrInvalidParameter, entities.MinChampionLevel, entities.MaxChampionLevel)
+	}
+
+	switch o.Ranking {
+	case "":
+		o.Ranking = entities.RankingCompetition
+	case entities.RankingCompetition, entities.RankingDense:
+	default:
+		return o, fmt.Errorf("%w: unknown ranking method %q", ErrInvalidParameter, o.Ranking)
+	}
+
+	if o.Locale == "" {
+		o.Locale = entities.DefaultLocale
+	}
+
+	return o, nil
+}
+
+// sameValue treats values within float rounding noise of the growth formula
+// as a tie.
+func sameValue(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
 }
@@ -0,0 +1,11 @@
+package usecases
+
+import "errors"
+
+var (
+	// ErrInvalidParameter is wrapped by errors caused by invalid caller input.
+	ErrInvalidParameter = errors.New("invalid parameter")
+	// ErrNotFound is wrapped by errors reporting that a requested resource
+	// does not exist.
+	ErrNotFound = errors.New("not found")
+)
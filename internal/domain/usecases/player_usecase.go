@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+)
+
+const (
+	DefaultMasteryCount = 5
+	MaxMasteryCount     = 20
+)
+
+type PlayerUseCase interface {
+	// GetTopMasteryChampions resolves riotID ("GameName#TagLine") and returns
+	// the player's count highest mastery champions on platform together with
+	// their movement speed rank. An empty platform means
+	// entities.DefaultPlatform and a count of zero means DefaultMasteryCount.
+	GetTopMasteryChampions(ctx context.Context, platform entities.Platform, riotID string, count int) (*entities.PlayerMastery, error)
+}
+
+type PlayerRepository interface {
+	GetAccountByRiotID(ctx context.Context, platform entities.Platform, gameName, tagLine string) (*entities.Account, error)
+	GetTopChampionMasteries(ctx context.Context, platform entities.Platform, puuid string, count int) ([]entities.ChampionMastery, error)
+}
+
+type PlayerUseCaseImpl struct {
+	playerRepo   PlayerRepository
+	championRepo ChampionRepository
+}
+
+func NewPlayerUseCase(playerRepo PlayerRepository, championRepo ChampionRepository) PlayerUseCase {
+	return &PlayerUseCaseImpl{
+		playerRepo:   playerRepo,
+		championRepo: championRepo,
+	}
+}
+
+func (uc *PlayerUseCaseImpl) GetTopMasteryChampions(ctx context.Context, platform entities.Platform, riotID string, count int) (*entities.PlayerMastery, error) {
+	platform, err := entities.ParsePlatform(string(platform))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	gameName, tagLine, err := entities.ParseRiotID(riotID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	if count == 0 {
+		count = DefaultMasteryCount
+	}
+	if count < 1 || count > MaxMasteryCount {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", ErrInvalidParameter, MaxMasteryCount)
+	}
+
+	account, err := uc.playerRepo.GetAccountByRiotID(ctx, platform, gameName, tagLine)
+	if err != nil {
+		return nil, err
+	}
+
+	masteries, err := uc.playerRepo.GetTopChampionMasteries(ctx, platform, account.PUUID, count)
+	if err != nil {
+		return nil, err
+	}
+
+	champions, err := uc.championRepo.GetAllChampions(ctx, entities.DefaultLocale)
+	if err != nil {
+		return nil, err
+	}
+	ranked, err := rankChampions(champions, RankOptions{
+		Stat:    entities.StatMoveSpeed,
+		Order:   entities.SortDescending,
+		Level:   entities.MinChampionLevel,
+		Ranking: entities.RankingCompetition,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]entities.RankedChampion, len(ranked))
+	for _, champion := range ranked {
+		byKey[champion.Champion.Key] = champion
+	}
+
+	result := &entities.PlayerMastery{
+		Platform:  platform,
+		Account:   *account,
+		Champions: make([]entities.MasteryChampion, 0, len(masteries)),
+	}
+	for _, mastery := range masteries {
+		entry := entities.MasteryChampion{Mastery: mastery}
+		if champion, ok := byKey[strconv.FormatInt(mastery.ChampionID, 10)]; ok {
+			entry.Champion = &champion.Champion
+			entry.MovementSpeedRank = champion.Rank
+		}
+		result.Champions = append(result.Champions, entry)
+	}
+
+	return result, nil
+}
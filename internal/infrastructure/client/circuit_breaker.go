@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+// ErrCircuitOpen is wrapped by the error returned for requests rejected by an
+// open circuit breaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type BreakerState int
+
+const (
+	StateClosed BreakerState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens
+	// the circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before letting a
+	// trial request through in the half-open state.
+	OpenTimeout time.Duration
+}
+
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// BreakerSnapshot is the state of a host's circuit breaker at a point in time.
+type BreakerSnapshot struct {
+	Host                string       `json:"host"`
+	State               BreakerState `json:"-"`
+	StateName           string       `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	OpenedAt            *time.Time   `json:"openedAt,omitempty"`
+	LastError           string       `json:"lastError,omitempty"`
+}
+
+// CircuitBreakers keeps one circuit breaker per upstream host.
+type CircuitBreakers struct {
+	config   BreakerConfig
+	logger   logger.Logger
+	now      func() time.Time
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+func NewCircuitBreakers(config BreakerConfig, logger logger.Logger) *CircuitBreakers {
+	defaults := DefaultBreakerConfig()
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaults.FailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaults.OpenTimeout
+	}
+
+	return &CircuitBreakers{
+		config:   config,
+		logger:   logger,
+		now:      time.Now,
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+func WithCircuitBreakers(breakers *CircuitBreakers) Option {
+	return func(c *HTTPClientImpl) {
+		c.breakers = breakers
+	}
+}
+
+// Snapshots returns the state of every known host, ordered by host name.
+func (c *CircuitBreakers) Snapshots() []BreakerSnapshot {
+	c.mu.Lock()
+	breakers := make([]*circuitBreaker, 0, len(c.breakers))
+	for _, breaker := range c.breakers {
+		breakers = append(breakers, breaker)
+	}
+	c.mu.Unlock()
+
+	snapshots := make([]BreakerSnapshot, 0, len(breakers))
+	for _, breaker := range breakers {
+		snapshots = append(snapshots, breaker.snapshot())
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Host < snapshots[j].Host
+	})
+	return snapshots
+}
+
+func (c *CircuitBreakers) forHost(host string) *circuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker, ok := c.breakers[host]
+	if !ok {
+		breaker = &circuitBreaker{host: host, config: c.config, logger: c.logger, now: c.now}
+		c.breakers[host] = breaker
+	}
+	return breaker
+}
+
+type circuitBreaker struct {
+	host   string
+	config BreakerConfig
+	logger logger.Logger
+	now    func() time.Time
+
+	mu            sync.Mutex
+	state         BreakerState
+	failures      int
+	openedAt      time.Time
+	trialInFlight bool
+	lastError     string
+}
+
+// allow reports whether a request may be sent. In the half-open state only
+// one trial request is let through at a time.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		b.transition(StateHalfOpen)
+	}
+
+	switch b.state {
+	case StateOpen:
+		return fmt.Errorf("%w for %s", ErrCircuitOpen, b.host)
+	case StateHalfOpen:
+		if b.trialInFlight {
+			return fmt.Errorf("%w for %s", ErrCircuitOpen, b.host)
+		}
+		b.trialInFlight = true
+	}
+	return nil
+}
+
+func (b *circuitBreaker) record(resp *http.Response, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialInFlight = false
+
+	if err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		return
+	}
+
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	if !failed {
+		b.failures = 0
+		b.lastError = ""
+		if b.state != StateClosed {
+			b.transition(StateClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if err != nil {
+		b.lastError = err.Error()
+	} else {
+		b.lastError = fmt.Sprintf("status %d", resp.StatusCode)
+	}
+
+	if b.state == StateHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.openedAt = b.now()
+		if b.state != StateOpen {
+			b.transition(StateOpen)
+		}
+	}
+}
+
+// transition must be called with b.mu held.
+func (b *circuitBreaker) transition(to BreakerState) {
+	from := b.state
+	b.state = to
+
+	switch to {
+	case StateOpen:
+		b.logger.Warn("Circuit breaker for %s opened after %d consecutive failures (last: %s)", b.host, b.failures, b.lastError)
+	case StateHalfOpen:
+		b.logger.Info("Circuit breaker for %s half-open, sending trial request", b.host)
+	case StateClosed:
+		b.logger.Success("Circuit breaker for %s closed after being %s", b.host, from)
+	}
+}
+
+func (b *circuitBreaker) snapshot() BreakerSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := b.state
+	if state == StateOpen && b.now().Sub(b.openedAt) >= b.config.OpenTimeout {
+		state = StateHalfOpen
+	}
+
+	snapshot := BreakerSnapshot{
+		Host:                b.host,
+		State:               state,
+		StateName:           state.String(),
+		ConsecutiveFailures: b.failures,
+		LastError:           b.lastError,
+	}
+	if state != StateClosed {
+		openedAt := b.openedAt
+		snapshot.OpenedAt = &openedAt
+	}
+	return snapshot
+}
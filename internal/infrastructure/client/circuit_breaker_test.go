@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+// fakeClock is a clock that only moves when advanced or slept on.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Now()}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Sleep advances the clock by delay instead of waiting.
+func (c *fakeClock) Sleep(ctx context.Context, delay time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.Advance(delay)
+	return nil
+}
+
+func quietLogger() logger.Logger {
+	return logger.NewJSONLogger(io.Discard, logger.LevelError)
+}
+
+func newTestBreaker(clock *fakeClock) *circuitBreaker {
+	breakers := NewCircuitBreakers(BreakerConfig{FailureThreshold: 3, OpenTimeout: 30 * time.Second}, quietLogger())
+	breakers.now = clock.Now
+	return breakers.forHost("ddragon.leagueoflegends.com")
+}
+
+// Outcomes of a request recorded by a breaker step.
+const (
+	outcomeOK        = "200"
+	outcomeNotFound  = "404"
+	outcomeServer    = "500"
+	outcomeTransport = "transport error"
+	outcomeCanceled  = "canceled"
+	outcomeDeadline  = "deadline exceeded"
+)
+
+func record(b *circuitBreaker, outcome string) {
+	switch outcome {
+	case outcomeOK:
+		b.record(&http.Response{StatusCode: http.StatusOK}, nil)
+	case outcomeNotFound:
+		b.record(&http.Response{StatusCode: http.StatusNotFound}, nil)
+	case outcomeServer:
+		b.record(&http.Response{StatusCode: http.StatusInternalServerError}, nil)
+	case outcomeTransport:
+		b.record(nil, errors.New("dial tcp: connection refused"))
+	case outcomeCanceled:
+		b.record(nil, context.Canceled)
+	case outcomeDeadline:
+		b.record(nil, context.DeadlineExceeded)
+	}
+}
+
+// breakerStep advances the clock, then sends a request with outcome unless
+// outcome is empty, and checks the state of the breaker afterwards.
+type breakerStep struct {
+	advance   time.Duration
+	outcome   string
+	rejected  bool
+	wantState BreakerState
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	open := func(outcome string) []breakerStep {
+		return []breakerStep{
+			{outcome: outcome, wantState: StateClosed},
+			{outcome: outcome, wantState: StateClosed},
+			{outcome: outcome, wantState: StateOpen},
+		}
+	}
+	steps := func(groups ...[]breakerStep) []breakerStep {
+		var all []breakerStep
+		for _, group := range groups {
+			all = append(all, group...)
+		}
+		return all
+	}
+
+	tests := []struct {
+		name  string
+		steps []breakerStep
+	}{
+		{
+			name: "closed to open to half-open to closed",
+			steps: steps(open(outcomeServer), []breakerStep{
+				{outcome: outcomeOK, rejected: true, wantState: StateOpen},
+				{advance: 29 * time.Second, outcome: outcomeOK, rejected: true, wantState: StateOpen},
+				{advance: time.Second, wantState: StateHalfOpen},
+				{outcome: outcomeOK, wantState: StateClosed},
+				{outcome: outcomeServer, wantState: StateClosed},
+			}),
+		},
+		{
+			name: "half-open to open",
+			steps: steps(open(outcomeTransport), []breakerStep{
+				{advance: 30 * time.Second, outcome: outcomeServer, wantState: StateOpen},
+				{advance: 29 * time.Second, outcome: outcomeOK, rejected: true, wantState: StateOpen},
+				{advance: time.Second, outcome: outcomeOK, wantState: StateClosed},
+			}),
+		},
+		{
+			name: "success resets the failure count",
+			steps: []breakerStep{
+				{outcome: outcomeServer, wantState: StateClosed},
+				{outcome: outcomeTransport, wantState: StateClosed},
+				{outcome: outcomeOK, wantState: StateClosed},
+				{outcome: outcomeServer, wantState: StateClosed},
+				{outcome: outcomeServer, wantState: StateClosed},
+				{outcome: outcomeServer, wantState: StateOpen},
+			},
+		},
+		{
+			name:  "client errors are not failures",
+			steps: steps(open(outcomeServer)[:2], open(outcomeNotFound)[:2], open(outcomeServer)[:2]),
+		},
+		{
+			name: "cancellation is not a failure",
+			steps: steps(open(outcomeServer)[:2], []breakerStep{
+				{outcome: outcomeCanceled, wantState: StateClosed},
+				{outcome: outcomeDeadline, wantState: StateClosed},
+				{outcome: outcomeCanceled, wantState: StateClosed},
+				{outcome: outcomeServer, wantState: StateOpen},
+			}),
+		},
+		{
+			name: "cancelled trial keeps the circuit half-open",
+			steps: steps(open(outcomeServer), []breakerStep{
+				{advance: 30 * time.Second, outcome: outcomeCanceled, wantState: StateHalfOpen},
+				{outcome: outcomeOK, wantState: StateClosed},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			breaker := newTestBreaker(clock)
+
+			for i, step := range tt.steps {
+				clock.Advance(step.advance)
+				if step.outcome != "" {
+					err := breaker.allow()
+					if step.rejected != errors.Is(err, ErrCircuitOpen) {
+						t.Fatalf("step %d: allow returned %v, want rejected %v", i, err, step.rejected)
+					}
+					if err == nil {
+						record(breaker, step.outcome)
+					}
+				}
+				if state := breaker.snapshot().State; state != step.wantState {
+					t.Fatalf("step %d: state %s, want %s", i, state, step.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerAllowsOneTrialAtATime(t *testing.T) {
+	clock := newFakeClock()
+	breaker := newTestBreaker(clock)
+	for i := 0; i < 3; i++ {
+		breaker.allow()
+		record(breaker, outcomeServer)
+	}
+	clock.Advance(30 * time.Second)
+
+	if err := breaker.allow(); err != nil {
+		t.Fatalf("trial rejected: %v", err)
+	}
+	if err := breaker.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("second request during the trial returned %v, want %v", err, ErrCircuitOpen)
+	}
+	record(breaker, outcomeOK)
+	if err := breaker.allow(); err != nil {
+		t.Errorf("request after a successful trial rejected: %v", err)
+	}
+}
+
+func TestHTTPClientRejectsRequestsWhileCircuitOpen(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	breakers := NewCircuitBreakers(BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute}, quietLogger())
+	httpClient := NewHTTPClient(5*time.Second, WithCircuitBreakers(breakers))
+
+	for i := 0; i < 2; i++ {
+		var statusErr *StatusError
+		if _, err := httpClient.Get(context.Background(), server.URL); !errors.As(err, &statusErr) {
+			t.Fatalf("request %d returned %v, want the upstream status", i, err)
+		}
+	}
+	if _, err := httpClient.Get(context.Background(), server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("got error %v, want %v", err, ErrCircuitOpen)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+	if snapshots := breakers.Snapshots(); len(snapshots) != 1 || snapshots[0].State != StateOpen || snapshots[0].LastError != "status 502" {
+		t.Errorf("snapshots %+v, want one open breaker", snapshots)
+	}
+}
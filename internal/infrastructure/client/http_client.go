@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/tracing"
 )
 
 type HTTPClient interface {
@@ -15,15 +17,60 @@ type HTTPClient interface {
 }
 
 type HTTPClientImpl struct {
-	client *http.Client
+	client   *http.Client
+	retry    RetryPolicy
+	breakers *CircuitBreakers
+	limiter  *RateLimiter
+	observer RequestObserver
+	tracer   *tracing.Tracer
 }
 
-func NewHTTPClient(timeout time.Duration) HTTPClient {
-	return &HTTPClientImpl{
+// Option customizes an HTTPClientImpl created by NewHTTPClient.
+type Option func(*HTTPClientImpl)
+
+func NewHTTPClient(timeout time.Duration, options ...Option) HTTPClient {
+	c := &HTTPClientImpl{
 		client: &http.Client{
 			Timeout: timeout,
 		},
 	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// RequestObserver is notified of every request HTTPClientImpl sends,
+// including retries, with the response status code or the transport error.
+// Requests rejected by a circuit breaker or the rate limiter are not sent and
+// not observed.
+type RequestObserver interface {
+	ObserveRequest(host, method string, statusCode int, err error, duration time.Duration)
+}
+
+func WithRequestObserver(observer RequestObserver) Option {
+	return func(c *HTTPClientImpl) {
+		c.observer = observer
+	}
+}
+
+// WithTracer traces every attempt in a client span and propagates it to the
+// server in a traceparent header. Requests whose context carries no span
+// start a new trace.
+func WithTracer(tracer *tracing.Tracer) Option {
+	return func(c *HTTPClientImpl) {
+		c.tracer = tracer
+	}
+}
+
+// StatusError is returned by Get when the server answers with a status
+// other than 200 OK.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
 }
 
 func (c *HTTPClientImpl) Get(ctx context.Context, url string) ([]byte, error) {
@@ -32,14 +79,14 @@ func (c *HTTPClientImpl) Get(ctx context.Context, url string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.doWithRetry(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -51,7 +98,77 @@ func (c *HTTPClientImpl) Get(ctx context.Context, url string) ([]byte, error) {
 }
 
 func (c *HTTPClientImpl) Do(req *http.Request) (*http.Response, error) {
-	return c.client.Do(req)
+	return c.doWithRetry(req)
+}
+
+// send performs a single attempt of req. It first waits for the rate
+// limiter and then goes through the circuit breaker of the target host, when
+// either is configured. The span of a traced attempt includes both waits.
+func (c *HTTPClientImpl) send(req *http.Request) (resp *http.Response, err error) {
+	if c.tracer != nil {
+		var span *tracing.Span
+		req, span = c.startSpan(req)
+		defer func() {
+			if err == nil {
+				span.SetAttributes(tracing.Int("http.response.status_code", resp.StatusCode))
+				if resp.StatusCode >= http.StatusBadRequest {
+					span.SetStatus(tracing.StatusError, resp.Status)
+				}
+			}
+			span.RecordError(err)
+			span.End()
+		}()
+	}
+
+	method := rateLimitMethod(req.Context())
+	if c.limiter != nil {
+		if err := c.limiter.Wait(req.Context(), method); err != nil {
+			return nil, err
+		}
+	}
+
+	var breaker *circuitBreaker
+	if c.breakers != nil {
+		breaker = c.breakers.forHost(req.URL.Host)
+		if err := breaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	resp, err = c.client.Do(req)
+
+	if c.observer != nil {
+		statusCode := 0
+		if err == nil {
+			statusCode = resp.StatusCode
+		}
+		c.observer.ObserveRequest(req.URL.Host, req.Method, statusCode, err, time.Since(start))
+	}
+	if breaker != nil {
+		breaker.record(resp, err)
+	}
+	if c.limiter != nil && err == nil {
+		c.limiter.Update(method, resp)
+	}
+	return resp, err
+}
+
+// startSpan starts the client span of an attempt and returns a copy of req
+// carrying it in its context and traceparent header.
+func (c *HTTPClientImpl) startSpan(req *http.Request) (*http.Request, *tracing.Span) {
+	ctx, span := c.tracer.Start(req.Context(), req.Method+" "+req.URL.Host,
+		tracing.WithSpanKind(tracing.SpanKindClient),
+		tracing.WithAttributes(
+			tracing.String("http.request.method", req.Method),
+			tracing.String("server.address", req.URL.Host),
+			tracing.String("url.full", req.URL.String()),
+		),
+	)
+
+	traced := req.Clone(ctx)
+	tracing.Inject(ctx, traced.Header)
+	return traced, span
 }
 
 func ParseJSON(data []byte, v interface{}) error {
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/tracing"
+)
+
+// spanRecorder is a tracing.Exporter keeping every exported span.
+type spanRecorder struct {
+	mu    sync.Mutex
+	spans []tracing.SpanData
+}
+
+func (r *spanRecorder) Export(span tracing.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, span)
+}
+
+func (r *spanRecorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (r *spanRecorder) byKind(kind tracing.SpanKind) []tracing.SpanData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var spans []tracing.SpanData
+	for _, span := range r.spans {
+		if span.Kind == kind {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+func TestTracePropagation(t *testing.T) {
+	recorder := &spanRecorder{}
+	tracer := tracing.NewTracer("test", recorder)
+
+	var received string
+	server := httptest.NewServer(tracer.Handler("/champions", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(tracing.TraceParentHeader)
+		w.Write([]byte("ok"))
+	})))
+	defer server.Close()
+
+	ctx, root := tracer.Start(context.Background(), "request")
+	if _, err := NewHTTPClient(5*time.Second, WithTracer(tracer)).Get(ctx, server.URL+"/champions"); err != nil {
+		t.Fatal(err)
+	}
+	root.End()
+
+	clientSpans := recorder.byKind(tracing.SpanKindClient)
+	serverSpans := recorder.byKind(tracing.SpanKindServer)
+	if len(clientSpans) != 1 || len(serverSpans) != 1 {
+		t.Fatalf("exported %d client and %d server spans, want one of each", len(clientSpans), len(serverSpans))
+	}
+	clientSpan, serverSpan := clientSpans[0], serverSpans[0]
+
+	if received != clientSpan.SpanContext.TraceParent() {
+		t.Errorf("server received traceparent %q, want the client span %q", received, clientSpan.SpanContext.TraceParent())
+	}
+	traceID := root.SpanContext().TraceID
+	if clientSpan.SpanContext.TraceID != traceID || serverSpan.SpanContext.TraceID != traceID {
+		t.Errorf("client trace %s and server trace %s, want %s", clientSpan.SpanContext.TraceID, serverSpan.SpanContext.TraceID, traceID)
+	}
+	if clientSpan.ParentSpanID != root.SpanContext().SpanID {
+		t.Errorf("client span parent %s, want the root span %s", clientSpan.ParentSpanID, root.SpanContext().SpanID)
+	}
+	if serverSpan.ParentSpanID != clientSpan.SpanContext.SpanID {
+		t.Errorf("server span parent %s, want the client span %s", serverSpan.ParentSpanID, clientSpan.SpanContext.SpanID)
+	}
+}
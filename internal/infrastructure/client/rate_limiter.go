@@ -0,0 +1,299 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited is wrapped by the error returned when a request cannot be
+// sent within the rate limits before its context deadline.
+var ErrRateLimited = errors.New("rate limit would be exceeded before the context deadline")
+
+// RateLimitWindow allows Limit requests every Period.
+type RateLimitWindow struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRateLimit parses the Riot "limit:seconds" list format used by the
+// X-App-Rate-Limit and X-Method-Rate-Limit headers, e.g. "20:1,100:120".
+func ParseRateLimit(value string) ([]RateLimitWindow, error) {
+	return parseRateLimitList(value, 1)
+}
+
+// parseRateLimitCount parses the matching *-Count headers, whose counts may
+// be zero.
+func parseRateLimitCount(value string) ([]RateLimitWindow, error) {
+	return parseRateLimitList(value, 0)
+}
+
+func parseRateLimitList(value string, minCount int) ([]RateLimitWindow, error) {
+	var windows []RateLimitWindow
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid rate limit %q", part)
+		}
+		limit, err := strconv.Atoi(fields[0])
+		if err != nil || limit < minCount {
+			return nil, fmt.Errorf("invalid rate limit count %q", fields[0])
+		}
+		seconds, err := strconv.Atoi(fields[1])
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid rate limit period %q", fields[1])
+		}
+
+		windows = append(windows, RateLimitWindow{Limit: limit, Period: time.Duration(seconds) * time.Second})
+	}
+	return windows, nil
+}
+
+// RateLimiter is a client-side token bucket limiter with any number of
+// stacked windows, e.g. 20 requests per second and 100 per two minutes. An
+// application scope applies to every request; method scopes apply to
+// requests tagged with WithRateLimitMethod. Both adjust themselves from the
+// rate limit headers of the responses.
+type RateLimiter struct {
+	mu      sync.Mutex
+	app     *limitScope
+	methods map[string]*limitScope
+
+	now   func() time.Time
+	sleep func(ctx context.Context, delay time.Duration) error
+}
+
+type limitScope struct {
+	buckets      []*tokenBucket
+	blockedUntil time.Time
+}
+
+type tokenBucket struct {
+	window RateLimitWindow
+	tokens float64
+	last   time.Time
+}
+
+func NewRateLimiter(windows ...RateLimitWindow) *RateLimiter {
+	return newRateLimiter(time.Now, sleep, windows)
+}
+
+// newRateLimiter returns a limiter reading the time from now and waiting with
+// sleep, which tests replace with a fake clock.
+func newRateLimiter(now func() time.Time, sleep func(ctx context.Context, delay time.Duration) error, windows []RateLimitWindow) *RateLimiter {
+	return &RateLimiter{
+		app:     newLimitScope(now(), windows),
+		methods: make(map[string]*limitScope),
+		now:     now,
+		sleep:   sleep,
+	}
+}
+
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *HTTPClientImpl) {
+		c.limiter = limiter
+	}
+}
+
+type rateLimitMethodKey struct{}
+
+// WithRateLimitMethod tags requests made with ctx with a method name, so that
+// they share the method rate limit learned from X-Method-Rate-Limit.
+func WithRateLimitMethod(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, rateLimitMethodKey{}, method)
+}
+
+func rateLimitMethod(ctx context.Context) string {
+	method, _ := ctx.Value(rateLimitMethodKey{}).(string)
+	return method
+}
+
+// Wait blocks until a request for method fits in every window, or fails with
+// ErrRateLimited as soon as it is clear that ctx expires first.
+func (l *RateLimiter) Wait(ctx context.Context, method string) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		scopes := []*limitScope{l.app}
+		if scope, ok := l.methods[method]; ok && method != "" {
+			scopes = append(scopes, scope)
+		}
+
+		var wait time.Duration
+		for _, scope := range scopes {
+			if scopeWait := scope.reserveWait(now); scopeWait > wait {
+				wait = scopeWait
+			}
+		}
+		if wait == 0 {
+			for _, scope := range scopes {
+				scope.take()
+			}
+		}
+		l.mu.Unlock()
+
+		if wait == 0 {
+			return nil
+		}
+		if deadline, ok := ctx.Deadline(); ok && deadline.Sub(l.now()) < wait {
+			return fmt.Errorf("%w (wait %s)", ErrRateLimited, wait.Round(time.Millisecond))
+		}
+		if err := l.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Update adjusts the limiter from the rate limit headers of resp. Limits
+// replace the configured windows, counts drain the buckets to what the
+// server has already seen, and a 429 blocks the scope named by
+// X-Rate-Limit-Type for Retry-After.
+func (l *RateLimiter) Update(method string, resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.app.update(now, resp.Header.Get("X-App-Rate-Limit"), resp.Header.Get("X-App-Rate-Limit-Count"))
+
+	var methodScope *limitScope
+	if method != "" {
+		if windows, err := ParseRateLimit(resp.Header.Get("X-Method-Rate-Limit")); err == nil && len(windows) > 0 {
+			methodScope = l.methods[method]
+			if methodScope == nil {
+				methodScope = newLimitScope(now, nil)
+				l.methods[method] = methodScope
+			}
+			methodScope.update(now, resp.Header.Get("X-Method-Rate-Limit"), resp.Header.Get("X-Method-Rate-Limit-Count"))
+		}
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"))
+	if !ok {
+		retryAfter = time.Second
+	}
+	scope := l.app
+	if resp.Header.Get("X-Rate-Limit-Type") == "method" && methodScope != nil {
+		scope = methodScope
+	}
+	scope.blockedUntil = now.Add(retryAfter)
+}
+
+func newLimitScope(now time.Time, windows []RateLimitWindow) *limitScope {
+	scope := &limitScope{}
+	scope.setWindows(now, windows)
+	return scope
+}
+
+func (s *limitScope) setWindows(now time.Time, windows []RateLimitWindow) {
+	buckets := make([]*tokenBucket, 0, len(windows))
+	for _, window := range windows {
+		bucket := &tokenBucket{window: window, tokens: float64(window.Limit), last: now}
+		for _, existing := range s.buckets {
+			if existing.window.Period == window.Period {
+				existing.refill(now)
+				bucket.tokens = existing.tokens
+				if bucket.tokens > float64(window.Limit) {
+					bucket.tokens = float64(window.Limit)
+				}
+			}
+		}
+		buckets = append(buckets, bucket)
+	}
+	s.buckets = buckets
+}
+
+func (s *limitScope) update(now time.Time, limitHeader, countHeader string) {
+	if windows, err := ParseRateLimit(limitHeader); err == nil && len(windows) > 0 && !s.hasWindows(windows) {
+		s.setWindows(now, windows)
+	}
+
+	counts, err := parseRateLimitCount(countHeader)
+	if err != nil {
+		return
+	}
+	for _, count := range counts {
+		for _, bucket := range s.buckets {
+			if bucket.window.Period != count.Period {
+				continue
+			}
+			bucket.refill(now)
+			if remaining := float64(bucket.window.Limit - count.Limit); bucket.tokens > remaining {
+				bucket.tokens = remaining
+			}
+		}
+	}
+}
+
+func (s *limitScope) hasWindows(windows []RateLimitWindow) bool {
+	if len(windows) != len(s.buckets) {
+		return false
+	}
+	for i, window := range windows {
+		if s.buckets[i].window != window {
+			return false
+		}
+	}
+	return true
+}
+
+// reserveWait returns how long to wait before every bucket of the scope
+// holds a token.
+func (s *limitScope) reserveWait(now time.Time) time.Duration {
+	var wait time.Duration
+	if now.Before(s.blockedUntil) {
+		wait = s.blockedUntil.Sub(now)
+	}
+	for _, bucket := range s.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= 1 {
+			continue
+		}
+		rate := float64(bucket.window.Limit) / bucket.window.Period.Seconds()
+		if bucketWait := time.Duration((1 - bucket.tokens) / rate * float64(time.Second)); bucketWait > wait {
+			wait = bucketWait
+		}
+	}
+	if wait > 0 && wait < time.Millisecond {
+		wait = time.Millisecond
+	}
+	return wait
+}
+
+func (s *limitScope) take() {
+	for _, bucket := range s.buckets {
+		bucket.tokens--
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+	b.last = now
+	b.tokens += elapsed.Seconds() * float64(b.window.Limit) / b.window.Period.Seconds()
+	if b.tokens > float64(b.window.Limit) {
+		b.tokens = float64(b.window.Limit)
+	}
+}
+
+// RiotDevelopmentRateLimits are the application limits of a Riot development
+// API key. They are refined by the X-App-Rate-Limit header of the first
+// response.
+var RiotDevelopmentRateLimits = []RateLimitWindow{
+	{Limit: 20, Period: time.Second},
+	{Limit: 100, Period: 2 * time.Minute},
+}
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(clock *fakeClock, windows ...RateLimitWindow) *RateLimiter {
+	return newRateLimiter(clock.Now, clock.Sleep, windows)
+}
+
+// waitAll waits for n requests of method and returns how long they took on
+// clock.
+func waitAll(t *testing.T, limiter *RateLimiter, clock *fakeClock, method string, n int) time.Duration {
+	t.Helper()
+
+	start := clock.Now()
+	for i := 0; i < n; i++ {
+		if err := limiter.Wait(context.Background(), method); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	return clock.Now().Sub(start)
+}
+
+func assertElapsed(t *testing.T, got, want time.Duration) {
+	t.Helper()
+	if diff := got - want; diff < -time.Millisecond || diff > time.Millisecond {
+		t.Errorf("waited %s, want %s", got, want)
+	}
+}
+
+func rateLimitResponse(status int, header map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: make(http.Header)}
+	for key, value := range header {
+		resp.Header.Set(key, value)
+	}
+	return resp
+}
+
+func TestRateLimiterStackedWindows(t *testing.T) {
+	clock := newFakeClock()
+	limiter := newTestLimiter(clock,
+		RateLimitWindow{Limit: 2, Period: time.Second},
+		RateLimitWindow{Limit: 3, Period: 10 * time.Second},
+	)
+
+	assertElapsed(t, waitAll(t, limiter, clock, "", 2), 0)
+	// The third request waits for the per-second window to refill half a
+	// token's worth, the fourth for the ten-second window.
+	assertElapsed(t, waitAll(t, limiter, clock, "", 1), 500*time.Millisecond)
+	assertElapsed(t, waitAll(t, limiter, clock, "", 1), 2833*time.Millisecond)
+}
+
+func TestRateLimiterUpdatesFromCountHeaders(t *testing.T) {
+	clock := newFakeClock()
+	limiter := newTestLimiter(clock, RateLimitWindow{Limit: 20, Period: time.Second})
+
+	// The server has already seen every request of this second, e.g. from
+	// another replica, and reports a two-minute window as well.
+	limiter.Update("", rateLimitResponse(http.StatusOK, map[string]string{
+		"X-App-Rate-Limit":       "20:1,100:120",
+		"X-App-Rate-Limit-Count": "20:1,99:120",
+	}))
+
+	assertElapsed(t, waitAll(t, limiter, clock, "", 1), 50*time.Millisecond)
+	// That request took the last token of the two-minute window, which
+	// refills one every 1.2s, 50ms of which have passed.
+	assertElapsed(t, waitAll(t, limiter, clock, "", 1), 1150*time.Millisecond)
+}
+
+func TestRateLimiterMethodScope(t *testing.T) {
+	clock := newFakeClock()
+	limiter := newTestLimiter(clock, RateLimitWindow{Limit: 100, Period: time.Second})
+	const method = "champion-mastery-v4.top-by-puuid"
+
+	// Without a method limit from the server, only the application limit
+	// applies.
+	assertElapsed(t, waitAll(t, limiter, clock, method, 5), 0)
+
+	limiter.Update(method, rateLimitResponse(http.StatusOK, map[string]string{
+		"X-Method-Rate-Limit":       "2:10",
+		"X-Method-Rate-Limit-Count": "2:10",
+	}))
+
+	assertElapsed(t, waitAll(t, limiter, clock, method, 1), 5*time.Second)
+	assertElapsed(t, waitAll(t, limiter, clock, "account-v1.by-riot-id", 5), 0)
+	assertElapsed(t, waitAll(t, limiter, clock, "", 5), 0)
+}
+
+func TestRateLimiterTooManyRequests(t *testing.T) {
+	const method = "account-v1.by-riot-id"
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		// waits holds how long the next request of each method waits.
+		waits map[string]time.Duration
+	}{
+		{
+			name:   "application limit",
+			header: map[string]string{"Retry-After": "7", "X-Rate-Limit-Type": "application"},
+			waits:  map[string]time.Duration{"": 7 * time.Second, method: 7 * time.Second},
+		},
+		{
+			name: "method limit",
+			header: map[string]string{
+				"Retry-After":         "7",
+				"X-Rate-Limit-Type":   "method",
+				"X-Method-Rate-Limit": "100:10",
+			},
+			waits: map[string]time.Duration{"": 0, "other": 0, method: 7 * time.Second},
+		},
+		{
+			name:   "without Retry-After",
+			header: map[string]string{"X-Rate-Limit-Type": "service"},
+			waits:  map[string]time.Duration{"": time.Second, method: time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		for name, wait := range tt.waits {
+			t.Run(tt.name+"/"+name, func(t *testing.T) {
+				clock := newFakeClock()
+				limiter := newTestLimiter(clock, RateLimitWindow{Limit: 100, Period: time.Second})
+				limiter.Update(method, rateLimitResponse(http.StatusTooManyRequests, tt.header))
+
+				assertElapsed(t, waitAll(t, limiter, clock, name, 1), wait)
+			})
+		}
+	}
+}
+
+func TestRateLimiterGivesUpBeforeDeadline(t *testing.T) {
+	clock := newFakeClock()
+	limiter := newTestLimiter(clock, RateLimitWindow{Limit: 1, Period: 10 * time.Second})
+	waitAll(t, limiter, clock, "", 1)
+
+	ctx, cancel := context.WithDeadline(context.Background(), clock.Now().Add(time.Second))
+	defer cancel()
+	err := limiter.Wait(ctx, "")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("got error %v, want %v", err, ErrRateLimited)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), clock.Now().Add(time.Minute))
+	defer cancel()
+	if err := limiter.Wait(ctx, ""); err != nil {
+		t.Errorf("request within the deadline failed: %v", err)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	windows, err := ParseRateLimit("20:1, 100:120")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RateLimitWindow{{Limit: 20, Period: time.Second}, {Limit: 100, Period: 2 * time.Minute}}
+	if len(windows) != 2 || windows[0] != want[0] || windows[1] != want[1] {
+		t.Errorf("got %+v, want %+v", windows, want)
+	}
+
+	for _, value := range []string{"20", "0:1", "20:0", "a:1", "20:1:2"} {
+		if _, err := ParseRateLimit(value); err == nil {
+			t.Errorf("%q parsed without an error", value)
+		}
+	}
+	if _, err := parseRateLimitCount("0:1"); err != nil {
+		t.Errorf("count of 0 rejected: %v", err)
+	}
+}
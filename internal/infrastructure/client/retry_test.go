@@ -0,0 +1,246 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyServer answers the first failures requests with status and
+// header, and every later one with 200 OK.
+func newFlakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newRetryingClient(policy RetryPolicy) HTTPClient {
+	return NewHTTPClient(5*time.Second, WithRetryPolicy(policy))
+}
+
+var fastRetries = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func TestRetryAttempts(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		status       int
+		wantRequests int32
+		wantStatus   int
+	}{
+		{name: "success", failures: 0, status: http.StatusServiceUnavailable, wantRequests: 1},
+		{name: "recovers", failures: 2, status: http.StatusServiceUnavailable, wantRequests: 3},
+		{name: "gives up", failures: 5, status: http.StatusBadGateway, wantRequests: 3, wantStatus: http.StatusBadGateway},
+		{name: "too many requests", failures: 1, status: http.StatusTooManyRequests, wantRequests: 2},
+		{name: "not retryable", failures: 1, status: http.StatusNotFound, wantRequests: 1, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newFlakyServer(t, tt.failures, tt.status, nil)
+
+			body, err := newRetryingClient(fastRetries).Get(context.Background(), server.URL)
+
+			if got := atomic.LoadInt32(requests); got != tt.wantRequests {
+				t.Errorf("%d requests, want %d", got, tt.wantRequests)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil || string(body) != "ok" {
+					t.Errorf("got %q, %v, want ok", body, err)
+				}
+				return
+			}
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus {
+				t.Errorf("got error %v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRetryNonIdempotentMethods(t *testing.T) {
+	for _, method := range []string{http.MethodPost, http.MethodPatch} {
+		t.Run(method, func(t *testing.T) {
+			server, requests := newFlakyServer(t, 5, http.StatusServiceUnavailable, nil)
+
+			req, err := http.NewRequest(method, server.URL, strings.NewReader(`{}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := newRetryingClient(fastRetries).Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("status %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+			}
+			if got := atomic.LoadInt32(requests); got != 1 {
+				t.Errorf("%d requests, want 1", got)
+			}
+		})
+	}
+}
+
+func TestRetryIdempotentRequestWithBody(t *testing.T) {
+	var bodies []string
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		bodies = append(bodies, string(body))
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := newRetryingClient(fastRetries).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 2 || bodies[0] != "payload" || bodies[1] != "payload" {
+		t.Errorf("server received bodies %q, want the payload twice", bodies)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := map[string]func() string{
+		"seconds": func() string { return "1" },
+		"HTTP date": func() string {
+			return time.Now().Add(2 * time.Second).UTC().Format(http.TimeFormat)
+		},
+	}
+	policy := RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: 3 * time.Second}
+
+	for name, header := range tests {
+		t.Run(name, func(t *testing.T) {
+			retryAfter := header()
+			server, requests := newFlakyServer(t, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {retryAfter}})
+
+			start := time.Now()
+			if _, err := newRetryingClient(policy).Get(context.Background(), server.URL); err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+				t.Errorf("retried after %s, want to wait for Retry-After %s", elapsed, retryAfter)
+			}
+			if got := atomic.LoadInt32(requests); got != 2 {
+				t.Errorf("%d requests, want 2", got)
+			}
+		})
+	}
+}
+
+func TestRetryAfterBeyondMaxDelay(t *testing.T) {
+	tests := map[string]string{
+		"seconds":   "3600",
+		"HTTP date": time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+	}
+
+	for name, retryAfter := range tests {
+		t.Run(name, func(t *testing.T) {
+			server, requests := newFlakyServer(t, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {retryAfter}})
+
+			start := time.Now()
+			_, err := newRetryingClient(fastRetries).Get(context.Background(), server.URL)
+
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("got error %v, want status 503", err)
+			}
+			if got := atomic.LoadInt32(requests); got != 1 {
+				t.Errorf("%d requests, want 1", got)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("returned after %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		value  string
+		min    time.Duration
+		max    time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "0", wantOK: true},
+		{value: "120", min: 2 * time.Minute, max: 2 * time.Minute, wantOK: true},
+		{value: "-1", wantOK: false},
+		{value: "soon", wantOK: false},
+		{value: now.Add(90 * time.Second).UTC().Format(http.TimeFormat), min: 88 * time.Second, max: 90 * time.Second, wantOK: true},
+		{value: now.Add(-time.Hour).UTC().Format(http.TimeFormat), wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			delay, ok := parseRetryAfter(tt.value)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if delay < tt.min || delay > tt.max {
+				t.Errorf("delay %s, want between %s and %s", delay, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	limits := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+
+	for i, limit := range limits {
+		attempt := i + 1
+		var longest time.Duration
+		for n := 0; n < 1000; n++ {
+			delay := policy.backoff(attempt)
+			if delay < 0 || delay > limit {
+				t.Fatalf("attempt %d: delay %s outside 0-%s", attempt, delay, limit)
+			}
+			if delay > longest {
+				longest = delay
+			}
+		}
+		if longest < limit/2 {
+			t.Errorf("attempt %d: longest of 1000 delays is %s, want jitter up to %s", attempt, longest, limit)
+		}
+	}
+}
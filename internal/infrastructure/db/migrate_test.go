@@ -0,0 +1,281 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+func quietLogger() logger.Logger {
+	return logger.NewJSONLogger(io.Discard, logger.LevelError)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(contents string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(contents)}
+	}
+
+	tests := []struct {
+		name         string
+		files        fstest.MapFS
+		wantVersions []int64
+		wantErr      string
+	}{
+		{
+			name: "ordered by numeric version",
+			files: fstest.MapFS{
+				"migrations/0010_ten.up.sql":   file("SELECT 10"),
+				"migrations/0002_two.up.sql":   file("SELECT 2"),
+				"migrations/0002_two.down.sql": file("SELECT -2"),
+				"migrations/1_one.up.sql":      file("SELECT 1"),
+			},
+			wantVersions: []int64{1, 2, 10},
+		},
+		{
+			name:    "invalid file name",
+			files:   fstest.MapFS{"migrations/create_champions.up.sql": file("SELECT 1")},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name:    "neither up nor down",
+			files:   fstest.MapFS{"migrations/0001_one.sideways.sql": file("SELECT 1")},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name: "conflicting names",
+			files: fstest.MapFS{
+				"migrations/0001_one.up.sql":   file("SELECT 1"),
+				"migrations/0001_uno.down.sql": file("SELECT -1"),
+			},
+			wantErr: "conflicting names",
+		},
+		{
+			name:    "down without up",
+			files:   fstest.MapFS{"migrations/0001_one.down.sql": file("SELECT -1")},
+			wantErr: "has no up script",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.files)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var versions []int64
+			for _, migration := range migrations {
+				versions = append(versions, migration.Version)
+			}
+			if len(versions) != len(tt.wantVersions) {
+				t.Fatalf("versions %v, want %v", versions, tt.wantVersions)
+			}
+			for i := range versions {
+				if versions[i] != tt.wantVersions[i] {
+					t.Fatalf("versions %v, want %v", versions, tt.wantVersions)
+				}
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d_%s at position %d, want versions numbered from 1 without gaps", migration.Version, migration.Name, i)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s lacks an up or a down script", migration.Version, migration.Name)
+		}
+	}
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "one", Up: "CREATE TABLE one (id INT)", Down: "DROP TABLE one"},
+	{Version: 2, Name: "two", Up: "CREATE TABLE two (id INT)", Down: "DROP TABLE two"},
+	{Version: 3, Name: "three", Up: "CREATE TABLE three (id INT)", Down: "DROP TABLE three"},
+}
+
+// expectLocked expects the statements withLock runs before its function,
+// with the given versions already applied.
+func expectLocked(mock sqlmock.Sqlmock, applied ...int64) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_lock($1)")).
+		WithArgs(int64(migrationLockID)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range applied {
+		rows.AddRow(version, time.Now())
+	}
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_unlock($1)")).
+		WithArgs(int64(migrationLockID)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectApply(mock sqlmock.Sqlmock, migration Migration) {
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(migration.Up)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(migration.Version, migration.Name, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return &Migrator{db: db, logger: quietLogger(), migrations: testMigrations}, mock
+}
+
+func TestMigratorUpAppliesPendingMigrationsInOrder(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+	expectLocked(mock, 1)
+	expectApply(mock, testMigrations[1])
+	expectApply(mock, testMigrations[2])
+	expectUnlock(mock)
+
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigratorUpWithNothingPending(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+	expectLocked(mock, 1, 2, 3)
+	expectUnlock(mock)
+
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigratorUpRollsBackFailedMigration(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+	failure := errors.New(`relation "two" already exists`)
+	expectLocked(mock)
+	expectApply(mock, testMigrations[0])
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(testMigrations[1].Up)).WillReturnError(failure)
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	err := migrator.Up(context.Background())
+	if !errors.Is(err, failure) || !strings.HasPrefix(err.Error(), "migration 2_two: ") {
+		t.Errorf("got error %v, want migration 2_two to fail", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMigratorUpFailsWithoutLock(t *testing.T) {
+	migrator, mock := newTestMigrator(t)
+	mock.ExpectExec("pg_advisory_lock").WillReturnError(context.Canceled)
+
+	if err := migrator.Up(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestMigratorIntegration runs the embedded migrations against the database
+// at TEST_DATABASE_URL. It reverts every migration, so the database must be
+// disposable.
+func TestMigratorIntegration(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	dsn, err := PostgresConfig{URL: url}.DSN()
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, quietLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	applied := func() int {
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		count := 0
+		for _, status := range statuses {
+			if status.AppliedAt != nil {
+				count++
+			}
+		}
+		return count
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := migrator.Up(ctx); err != nil {
+			t.Fatalf("up %d: %v", i, err)
+		}
+		if got := applied(); got != len(migrator.migrations) {
+			t.Fatalf("%d of %d migrations applied after up", got, len(migrator.migrations))
+		}
+	}
+
+	if err := migrator.Down(ctx, len(migrator.migrations)); err != nil {
+		t.Fatal(err)
+	}
+	if got := applied(); got != 0 {
+		t.Fatalf("%d migrations applied after reverting all of them", got)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS champions (
+	id SERIAL PRIMARY KEY,
+	champion_id VARCHAR(50) UNIQUE NOT NULL,
+	name VARCHAR(100) NOT NULL,
+	title VARCHAR(200) NOT NULL,
+	movement_speed FLOAT NOT NULL,
+	rank INT NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_champions_champion_id ON champions(champion_id);
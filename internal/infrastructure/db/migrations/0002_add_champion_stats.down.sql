@@ -0,0 +1,14 @@
+ALTER TABLE champions
+	DROP COLUMN IF EXISTS hp,
+	DROP COLUMN IF EXISTS hp_per_level,
+	DROP COLUMN IF EXISTS mp,
+	DROP COLUMN IF EXISTS mp_per_level,
+	DROP COLUMN IF EXISTS armor,
+	DROP COLUMN IF EXISTS armor_per_level,
+	DROP COLUMN IF EXISTS spell_block,
+	DROP COLUMN IF EXISTS spell_block_per_level,
+	DROP COLUMN IF EXISTS attack_range,
+	DROP COLUMN IF EXISTS attack_damage,
+	DROP COLUMN IF EXISTS attack_damage_per_level,
+	DROP COLUMN IF EXISTS attack_speed,
+	DROP COLUMN IF EXISTS attack_speed_per_level;
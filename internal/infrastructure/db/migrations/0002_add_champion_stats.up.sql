@@ -0,0 +1,14 @@
+ALTER TABLE champions
+	ADD COLUMN IF NOT EXISTS hp FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS hp_per_level FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS mp FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS mp_per_level FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS armor FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS armor_per_level FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS spell_block FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS spell_block_per_level FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS attack_range FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS attack_damage FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS attack_damage_per_level FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS attack_speed FLOAT NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS attack_speed_per_level FLOAT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS champion_stat_history;
+
+ALTER TABLE champions DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,16 @@
+ALTER TABLE champions ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE TABLE IF NOT EXISTS champion_stat_history (
+	id SERIAL PRIMARY KEY,
+	champion_id VARCHAR(50) NOT NULL,
+	stat VARCHAR(50) NOT NULL,
+	old_value FLOAT,
+	new_value FLOAT NOT NULL,
+	changed_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_champion_stat_history_champion_id
+	ON champion_stat_history(champion_id, changed_at);
+
+CREATE INDEX IF NOT EXISTS idx_champion_stat_history_changed_at
+	ON champion_stat_history(changed_at);
@@ -0,0 +1,2 @@
+ALTER TABLE champions
+	DROP COLUMN IF EXISTS locale;
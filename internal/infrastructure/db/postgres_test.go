@@ -0,0 +1,162 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+func TestDSNParam(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: `password=''`},
+		{value: "secret", want: `password='secret'`},
+		{value: "two words", want: `password='two words'`},
+		{value: "it's", want: `password='it\'s'`},
+		{value: `say "hi"`, want: `password='say "hi"'`},
+		{value: `back\slash`, want: `password='back\\slash'`},
+		{value: `\'`, want: `password='\\\''`},
+		{value: `a b='c' d=\`, want: `password='a b=\'c\' d=\\'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := dsnParam("password", tt.value); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  PostgresConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "settings",
+			config: PostgresConfig{
+				Host: "db", Port: 5432, User: "postgres", Password: `p a'ss\word"`,
+				DBName: "champions", SSLMode: "disable",
+			},
+			want: `host='db' port='5432' user='postgres' password='p a\'ss\\word"' dbname='champions' sslmode='disable'`,
+		},
+		{
+			name: "settings with certificates",
+			config: PostgresConfig{
+				Host: "db", Port: 5432, User: "postgres", DBName: "champions", SSLMode: "verify-full",
+				SSLRootCert: "/certs/ca.pem", SSLCert: "/certs/my client.pem", SSLKey: "/certs/client.key",
+			},
+			want: `host='db' port='5432' user='postgres' password='' dbname='champions' sslmode='verify-full' ` +
+				`sslrootcert='/certs/ca.pem' sslcert='/certs/my client.pem' sslkey='/certs/client.key'`,
+		},
+		{
+			name: "URL",
+			config: PostgresConfig{
+				URL:  "postgres://postgres:p%20a%27ss%5Cword@db:5432/champions?sslmode=require",
+				Host: "ignored", SSLRootCert: "/certs/ca.pem",
+			},
+			want: `dbname='champions' host='db' password='p a\'ss\\word' port='5432' sslmode='require' user='postgres' sslrootcert='/certs/ca.pem'`,
+		},
+		{
+			name:    "invalid URL",
+			config:  PostgresConfig{URL: "mysql://db/champions"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.DSN()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %s, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPingStopsWhenCancelled(t *testing.T) {
+	// Nothing listens on port 1, so every ping fails straight away.
+	db, err := sql.Open("postgres", "host=127.0.0.1 port=1 sslmode=disable connect_timeout=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	start := time.Now()
+	err = ping(ctx, db, time.Minute, logger.NewJSONLogger(io.Discard, logger.LevelError))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > 400*time.Millisecond {
+		t.Errorf("returned after %s, want to stop once ctx is done", elapsed)
+	}
+}
+
+func TestInvalidURLDoesNotLeakPassword(t *testing.T) {
+	urls := []string{
+		"postgres://postgres:hunter2@db:port/champions",
+		"postgres://postgres:hunter2@db:5432/champions%zz",
+		"mysql://postgres:hunter2@db/champions",
+	}
+
+	for _, dbURL := range urls {
+		t.Run(dbURL, func(t *testing.T) {
+			var logs bytes.Buffer
+			_, err := NewPostgresConnection(context.Background(), PostgresConfig{URL: dbURL}, logger.NewJSONLogger(&logs, logger.LevelDebug))
+			if err == nil {
+				t.Fatal("got no error for an invalid URL")
+			}
+			if strings.Contains(err.Error(), "hunter2") {
+				t.Errorf("error %q contains the password", err)
+			}
+			if strings.Contains(logs.String(), "hunter2") {
+				t.Errorf("logs %s contain the password", logs.String())
+			}
+		})
+	}
+}
+
+func TestInitializeDatabaseStopsWhenCancelled(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := InitializeDatabase(ctx, db, logger.NewJSONLogger(io.Discard, logger.LevelError)); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
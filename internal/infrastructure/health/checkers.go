@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
+)
+
+// PingChecker checks that db accepts connections.
+func PingChecker(db *sql.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	})
+}
+
+// MaxAgeChecker checks that the time returned by last, e.g. that of the last
+// successful sync, is at most maxAge ago. A zero time means it never
+// happened.
+func MaxAgeChecker(last func() time.Time, maxAge time.Duration) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		at := last()
+		if at.IsZero() {
+			return fmt.Errorf("never succeeded")
+		}
+		if age := time.Since(at); age > maxAge {
+			return fmt.Errorf("last succeeded %s ago, more than %s", age.Round(time.Second), maxAge)
+		}
+		return nil
+	})
+}
+
+// HTTPChecker checks that a GET of url answers with a status below 500. It
+// uses httpClient directly rather than a client.HTTPClient so that probes
+// are not retried or held back by circuit breakers.
+func HTTPChecker(httpClient *http.Client, url string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return &client.StatusError{StatusCode: resp.StatusCode}
+		}
+		return nil
+	})
+}
+
+// CircuitChecker checks that no circuit of breakers is open, i.e. that every
+// upstream host has been reachable recently.
+func CircuitChecker(breakers *client.CircuitBreakers) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		var open []string
+		for _, upstream := range breakers.Snapshots() {
+			if upstream.State == client.StateOpen {
+				open = append(open, upstream.Host)
+			}
+		}
+		if len(open) > 0 {
+			return fmt.Errorf("circuit open for %s", strings.Join(open, ", "))
+		}
+		return nil
+	})
+}
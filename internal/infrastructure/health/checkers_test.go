@@ -0,0 +1,121 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+func TestMaxAgeChecker(t *testing.T) {
+	tests := []struct {
+		name    string
+		last    time.Time
+		wantErr string
+	}{
+		{name: "before first success", last: time.Time{}, wantErr: "never succeeded"},
+		{name: "recent", last: time.Now().Add(-time.Minute)},
+		{name: "stale", last: time.Now().Add(-10 * time.Minute), wantErr: "last succeeded 10m0s ago, more than 5m0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MaxAgeChecker(func() time.Time { return tt.last }, 5*time.Minute).Check(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("got error %v, want none", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaxAgeCheckerFailsProbeUntilFirstSuccess(t *testing.T) {
+	var last time.Time
+	probe := NewProbe(time.Second, Check{
+		Name:     "sync",
+		Checker:  MaxAgeChecker(func() time.Time { return last }, time.Minute),
+		Critical: true,
+	})
+
+	if report := probe.Run(context.Background()); report.Status != StatusUnavailable {
+		t.Errorf("status %s before the first success, want %s", report.Status, StatusUnavailable)
+	}
+	last = time.Now()
+	if report := probe.Run(context.Background()); report.Status != StatusOK {
+		t.Errorf("status %s after a success, want %s", report.Status, StatusOK)
+	}
+}
+
+func TestHTTPChecker(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr bool
+	}{
+		{status: http.StatusOK},
+		{status: http.StatusNotFound},
+		{status: http.StatusInternalServerError, wantErr: true},
+		{status: http.StatusServiceUnavailable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := HTTPChecker(server.Client(), server.URL).Check(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error: %t", err, tt.wantErr)
+			}
+			var statusErr *client.StatusError
+			if tt.wantErr && (!errors.As(err, &statusErr) || statusErr.StatusCode != tt.status) {
+				t.Errorf("got error %v, want a StatusError with status %d", err, tt.status)
+			}
+		})
+	}
+}
+
+func TestHTTPCheckerUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	if err := HTTPChecker(http.DefaultClient, url).Check(context.Background()); err == nil {
+		t.Error("got no error for a closed server")
+	}
+}
+
+func TestCircuitChecker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	breakers := client.NewCircuitBreakers(client.BreakerConfig{FailureThreshold: 1, OpenTimeout: time.Minute}, logger.NewJSONLogger(io.Discard, logger.LevelError))
+	checker := CircuitChecker(breakers)
+
+	if err := checker.Check(context.Background()); err != nil {
+		t.Errorf("got error %v before any request, want none", err)
+	}
+
+	httpClient := client.NewHTTPClient(time.Second, client.WithCircuitBreakers(breakers))
+	httpClient.Get(context.Background(), server.URL)
+
+	err := checker.Check(context.Background())
+	if err == nil || !strings.Contains(err.Error(), strings.TrimPrefix(server.URL, "http://")) {
+		t.Errorf("got error %v, want the open circuit of %s", err, server.URL)
+	}
+}
@@ -0,0 +1,146 @@
+// Package health implements liveness and readiness probes made of pluggable
+// dependency checks, served as a JSON breakdown of every check.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds a single check when the probe has no timeout of its
+// own.
+const DefaultTimeout = 2 * time.Second
+
+// Checker reports whether a dependency is usable. It must return once ctx is
+// done.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to the Checker interface.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Check is a named Checker within a Probe. A failing critical check makes
+// the probe fail; a failing non-critical one only degrades it.
+type Check struct {
+	Name     string
+	Checker  Checker
+	Critical bool
+}
+
+// Status of a probe or of one of its checks.
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusFailed      = "failed"
+)
+
+// Probe runs its checks concurrently on every request. It answers 200 while
+// every critical check passes and 503 otherwise. A probe without checks
+// only reports that the process is serving requests, which is what a
+// liveness probe should do.
+type Probe struct {
+	timeout time.Duration
+	checks  []Check
+}
+
+func NewProbe(timeout time.Duration, checks ...Check) *Probe {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Probe{
+		timeout: timeout,
+		checks:  checks,
+	}
+}
+
+// CheckResult is the outcome of one check.
+type CheckResult struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	Critical   bool    `json:"critical"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of a probe, in the order its checks were given.
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// Run runs every check with the probe timeout.
+func (p *Probe) Run(ctx context.Context) Report {
+	results := make([]CheckResult, len(p.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range p.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = p.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status == StatusOK {
+			continue
+		}
+		if result.Critical {
+			report.Status = StatusUnavailable
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+func (p *Probe) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Checker.Check(ctx)
+
+	result := CheckResult{
+		Name:       check.Name,
+		Status:     StatusOK,
+		Critical:   check.Critical,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Handler serves the report of the probe as JSON.
+func (p *Probe) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := p.Run(r.Context())
+
+		body, err := json.Marshal(report)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status == StatusUnavailable {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		w.Write(body)
+	})
+}
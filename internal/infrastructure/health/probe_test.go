@@ -0,0 +1,163 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	passing = CheckerFunc(func(ctx context.Context) error { return nil })
+	failing = CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+)
+
+// serveProbe returns the status code and decoded report of a request to the
+// handler of probe.
+func serveProbe(t *testing.T, probe *Probe) (int, Report) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	probe.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type %q, want application/json", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control %q, want no-store", got)
+	}
+	var report Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decoding report %s: %v", rec.Body, err)
+	}
+	return rec.Code, report
+}
+
+func TestProbeHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		checks       []Check
+		wantCode     int
+		wantStatus   string
+		wantStatuses []string
+	}{
+		{
+			name:       "no checks",
+			wantCode:   http.StatusOK,
+			wantStatus: StatusOK,
+		},
+		{
+			name: "all passing",
+			checks: []Check{
+				{Name: "database", Checker: passing, Critical: true},
+				{Name: "upstream", Checker: passing},
+			},
+			wantCode:     http.StatusOK,
+			wantStatus:   StatusOK,
+			wantStatuses: []string{StatusOK, StatusOK},
+		},
+		{
+			name: "critical failing",
+			checks: []Check{
+				{Name: "database", Checker: failing, Critical: true},
+				{Name: "upstream", Checker: passing},
+			},
+			wantCode:     http.StatusServiceUnavailable,
+			wantStatus:   StatusUnavailable,
+			wantStatuses: []string{StatusFailed, StatusOK},
+		},
+		{
+			name: "non-critical failing",
+			checks: []Check{
+				{Name: "database", Checker: passing, Critical: true},
+				{Name: "upstream", Checker: failing},
+			},
+			wantCode:     http.StatusOK,
+			wantStatus:   StatusDegraded,
+			wantStatuses: []string{StatusOK, StatusFailed},
+		},
+		{
+			name: "both failing",
+			checks: []Check{
+				{Name: "upstream", Checker: failing},
+				{Name: "database", Checker: failing, Critical: true},
+			},
+			wantCode:     http.StatusServiceUnavailable,
+			wantStatus:   StatusUnavailable,
+			wantStatuses: []string{StatusFailed, StatusFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, report := serveProbe(t, NewProbe(time.Second, tt.checks...))
+
+			if code != tt.wantCode {
+				t.Errorf("status code %d, want %d", code, tt.wantCode)
+			}
+			if report.Status != tt.wantStatus {
+				t.Errorf("status %q, want %q", report.Status, tt.wantStatus)
+			}
+			if len(report.Checks) != len(tt.wantStatuses) {
+				t.Fatalf("got %d checks, want %d", len(report.Checks), len(tt.wantStatuses))
+			}
+			for i, result := range report.Checks {
+				check := tt.checks[i]
+				if result.Name != check.Name || result.Critical != check.Critical || result.Status != tt.wantStatuses[i] {
+					t.Errorf("check %d is %+v, want %s with status %s", i, result, check.Name, tt.wantStatuses[i])
+				}
+				if failed := result.Status == StatusFailed; failed != (result.Error != "") {
+					t.Errorf("check %s has status %s and error %q", result.Name, result.Status, result.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestProbeTimesOutSlowChecks(t *testing.T) {
+	slow := CheckerFunc(func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Second):
+			return nil
+		}
+	})
+	probe := NewProbe(50*time.Millisecond,
+		Check{Name: "database", Checker: slow, Critical: true},
+		Check{Name: "upstream", Checker: passing},
+	)
+
+	start := time.Now()
+	code, report := serveProbe(t, probe)
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("probe took %s, want it cut off after its 50ms timeout", elapsed)
+	}
+	if code != http.StatusServiceUnavailable || report.Status != StatusUnavailable {
+		t.Errorf("got %d %s, want %d %s", code, report.Status, http.StatusServiceUnavailable, StatusUnavailable)
+	}
+	if result := report.Checks[0]; result.Status != StatusFailed || result.Error != context.DeadlineExceeded.Error() || result.DurationMs < 50 {
+		t.Errorf("slow check %+v, want it failed with %v after at least 50ms", result, context.DeadlineExceeded)
+	}
+	if result := report.Checks[1]; result.Status != StatusOK {
+		t.Errorf("fast check %+v, want it unaffected", result)
+	}
+}
+
+func TestNewProbeDefaultsTimeout(t *testing.T) {
+	var deadline time.Duration
+	probe := NewProbe(0, Check{Name: "deadline", Checker: CheckerFunc(func(ctx context.Context) error {
+		at, _ := ctx.Deadline()
+		deadline = time.Until(at)
+		return nil
+	})})
+	probe.Run(context.Background())
+
+	if deadline <= DefaultTimeout-time.Second || deadline > DefaultTimeout {
+		t.Errorf("check ran with %s left, want about %s", deadline, DefaultTimeout)
+	}
+}
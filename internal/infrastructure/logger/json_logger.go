@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONLogger writes one JSON object per line with the time, level, message
+// and any fields added through With.
+type JSONLogger struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	level  Level
+	fields []interface{}
+}
+
+func NewJSONLogger(out io.Writer, level Level) Logger {
+	return &JSONLogger{
+		mu:    &sync.Mutex{},
+		out:   out,
+		level: level,
+	}
+}
+
+func (l *JSONLogger) Debug(format string, args ...interface{}) {
+	l.write(LevelDebug, "debug", format, args...)
+}
+
+func (l *JSONLogger) Info(format string, args ...interface{}) {
+	l.write(LevelInfo, "info", format, args...)
+}
+
+func (l *JSONLogger) Warn(format string, args ...interface{}) {
+	l.write(LevelWarn, "warn", format, args...)
+}
+
+func (l *JSONLogger) Error(format string, args ...interface{}) {
+	l.write(LevelError, "error", format, args...)
+}
+
+func (l *JSONLogger) Success(format string, args ...interface{}) {
+	l.write(LevelInfo, "success", format, args...)
+}
+
+func (l *JSONLogger) With(keysAndValues ...interface{}) Logger {
+	return &JSONLogger{
+		mu:     l.mu,
+		out:    l.out,
+		level:  l.level,
+		fields: appendFields(l.fields, keysAndValues),
+	}
+}
+
+func (l *JSONLogger) write(level Level, name string, format string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+
+	entry := make(map[string]interface{}, len(l.fields)/2+3)
+	for i := 0; i < len(l.fields); i += 2 {
+		value := l.fields[i+1]
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		entry[fmt.Sprint(l.fields[i])] = value
+	}
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = name
+	entry["msg"] = fmt.Sprintf(format, args...)
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(map[string]interface{}{
+			"time":  entry["time"],
+			"level": name,
+			"msg":   entry["msg"],
+			"error": fmt.Sprintf("failed to encode log fields: %v", err),
+		})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(append(line, '\n'))
+}
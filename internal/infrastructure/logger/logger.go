@@ -2,37 +2,124 @@ package logger
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
 )
 
 type Logger interface {
+	Debug(format string, args ...interface{})
 	Info(format string, args ...interface{})
+	Warn(format string, args ...interface{})
 	Error(format string, args ...interface{})
 	Success(format string, args ...interface{})
+	// With returns a child logger that adds the given key-value pairs to
+	// every entry it writes.
+	With(keysAndValues ...interface{}) Logger
 }
 
-type ConsoleLogger struct{}
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func ParseLevel(value string) (Level, error) {
+	switch strings.ToLower(value) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("unknown log level %q", value)
+	}
+}
+
+// New builds a logger writing in format, "console" (the default) or "json",
+// entries of at least level. It returns a console logger along with the
+// error when either value is unknown, so the error can still be logged.
+func New(format, level string) (Logger, error) {
+	minLevel, err := ParseLevel(level)
+	if err != nil {
+		return NewConsoleLogger(), err
+	}
+
+	switch strings.ToLower(format) {
+	case "", "console":
+		return &ConsoleLogger{level: minLevel}, nil
+	case "json":
+		return NewJSONLogger(os.Stdout, minLevel), nil
+	default:
+		return NewConsoleLogger(), fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+type ConsoleLogger struct {
+	level  Level
+	fields []interface{}
+}
 
 func NewConsoleLogger() Logger {
-	return &ConsoleLogger{}
+	return &ConsoleLogger{level: LevelInfo}
+}
+
+func (l *ConsoleLogger) Debug(format string, args ...interface{}) {
+	l.print(LevelDebug, color.New(color.FgCyan).SprintFunc()("[DEBUG]"), format, args...)
 }
 
 func (l *ConsoleLogger) Info(format string, args ...interface{}) {
-	blue := color.New(color.FgBlue).SprintFunc()
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Printf("%s %s %s\n", timestamp, blue("[INFO]"), fmt.Sprintf(format, args...))
+	l.print(LevelInfo, color.New(color.FgBlue).SprintFunc()("[INFO]"), format, args...)
+}
+
+func (l *ConsoleLogger) Warn(format string, args ...interface{}) {
+	l.print(LevelWarn, color.New(color.FgYellow).SprintFunc()("[WARN]"), format, args...)
 }
 
 func (l *ConsoleLogger) Error(format string, args ...interface{}) {
-	red := color.New(color.FgRed).SprintFunc()
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Printf("%s %s %s\n", timestamp, red("[ERROR]"), fmt.Sprintf(format, args...))
+	l.print(LevelError, color.New(color.FgRed).SprintFunc()("[ERROR]"), format, args...)
 }
 
 func (l *ConsoleLogger) Success(format string, args ...interface{}) {
-	green := color.New(color.FgGreen).SprintFunc()
+	l.print(LevelInfo, color.New(color.FgGreen).SprintFunc()("[SUCCESS]"), format, args...)
+}
+
+func (l *ConsoleLogger) With(keysAndValues ...interface{}) Logger {
+	return &ConsoleLogger{
+		level:  l.level,
+		fields: appendFields(l.fields, keysAndValues),
+	}
+}
+
+func (l *ConsoleLogger) print(level Level, label string, format string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	fmt.Printf("%s %s %s\n", timestamp, green("[SUCCESS]"), fmt.Sprintf(format, args...))
+	message := fmt.Sprintf(format, args...)
+	for i := 0; i < len(l.fields); i += 2 {
+		message += fmt.Sprintf(" %v=%v", l.fields[i], l.fields[i+1])
+	}
+	fmt.Printf("%s %s %s\n", timestamp, label, message)
+}
+
+// appendFields copies fields and appends keysAndValues, pairing a dangling
+// key with a "(MISSING)" value.
+func appendFields(fields []interface{}, keysAndValues []interface{}) []interface{} {
+	merged := make([]interface{}, 0, len(fields)+len(keysAndValues)+1)
+	merged = append(merged, fields...)
+	merged = append(merged, keysAndValues...)
+	if len(merged)%2 != 0 {
+		merged = append(merged, "(MISSING)")
+	}
+	return merged
 }
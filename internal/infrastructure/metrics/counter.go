@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+)
+
+// CounterVec is a family of monotonically increasing counters partitioned by
+// label values.
+type CounterVec struct {
+	metricName string
+	help       string
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		metricName: name,
+		help:       help,
+		labels:     labels,
+		series:     make(map[string]*counterSeries),
+	}
+	r.register(c)
+	return c
+}
+
+// Inc adds one to the counter with the given label values.
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add adds delta to the counter with the given label values. Counters never
+// decrease, so a negative delta is ignored.
+func (c *CounterVec) Add(delta float64, values ...string) {
+	if delta < 0 {
+		return
+	}
+	checkLabelValues(c.metricName, c.labels, values)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := labelKey(values)
+	series, ok := c.series[key]
+	if !ok {
+		series = &counterSeries{values: append([]string(nil), values...)}
+		c.series[key] = series
+	}
+	series.value += delta
+}
+
+func (c *CounterVec) name() string {
+	return c.metricName
+}
+
+func (c *CounterVec) write(buf *bytes.Buffer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, len(c.series))
+	for key := range c.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writeHeader(buf, c.metricName, c.help, "counter")
+	for _, key := range keys {
+		series := c.series[key]
+		writeSample(buf, c.metricName, c.labels, series.values, series.value)
+	}
+}
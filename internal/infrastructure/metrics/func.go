@@ -0,0 +1,33 @@
+package metrics
+
+import "bytes"
+
+// funcMetric reads its value from a callback on every scrape, for values
+// owned by other packages such as sql.DB pool statistics.
+type funcMetric struct {
+	metricName string
+	help       string
+	kind       string
+	value      func() float64
+}
+
+// NewGaugeFunc registers a gauge whose value is read from value on every
+// scrape.
+func (r *Registry) NewGaugeFunc(name, help string, value func() float64) {
+	r.register(&funcMetric{metricName: name, help: help, kind: "gauge", value: value})
+}
+
+// NewCounterFunc registers a counter whose value is read from value on every
+// scrape. value must never decrease.
+func (r *Registry) NewCounterFunc(name, help string, value func() float64) {
+	r.register(&funcMetric{metricName: name, help: help, kind: "counter", value: value})
+}
+
+func (f *funcMetric) name() string {
+	return f.metricName
+}
+
+func (f *funcMetric) write(buf *bytes.Buffer) {
+	writeHeader(buf, f.metricName, f.help, f.kind)
+	writeSample(buf, f.metricName, nil, nil, f.value())
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are latency buckets in seconds, from 5ms to 10s.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// HistogramVec is a family of histograms partitioned by label values.
+type HistogramVec struct {
+	metricName string
+	help       string
+	labels     []string
+	buckets    []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// NewHistogramVec creates a histogram with the given upper bucket bounds,
+// or DefaultBuckets when buckets is empty. The +Inf bucket is implicit.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	h := &HistogramVec{
+		metricName: name,
+		help:       help,
+		labels:     labels,
+		buckets:    buckets,
+		series:     make(map[string]*histogramSeries),
+	}
+	r.register(h)
+	return h
+}
+
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	checkLabelValues(h.metricName, h.labels, values)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := labelKey(values)
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{
+			values: append([]string(nil), values...),
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = series
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+		}
+	}
+	series.sum += value
+	series.count++
+}
+
+// ObserveDuration records d in seconds.
+func (h *HistogramVec) ObserveDuration(d time.Duration, values ...string) {
+	h.Observe(d.Seconds(), values...)
+}
+
+func (h *HistogramVec) name() string {
+	return h.metricName
+}
+
+func (h *HistogramVec) write(buf *bytes.Buffer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	bucketLabels := append(append([]string(nil), h.labels...), "le")
+
+	writeHeader(buf, h.metricName, h.help, "histogram")
+	for _, key := range keys {
+		series := h.series[key]
+		for i, bound := range h.buckets {
+			values := append(append([]string(nil), series.values...), formatValue(bound))
+			writeSample(buf, h.metricName+"_bucket", bucketLabels, values, float64(series.counts[i]))
+		}
+		values := append(append([]string(nil), series.values...), formatValue(math.Inf(1)))
+		writeSample(buf, h.metricName+"_bucket", bucketLabels, values, float64(series.count))
+		writeSample(buf, h.metricName+"_sum", h.labels, series.values, series.sum)
+		writeSample(buf, h.metricName+"_count", h.labels, series.values, float64(series.count))
+	}
+}
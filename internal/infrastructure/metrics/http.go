@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/httputil"
+)
+
+// ServerMetrics counts and times the requests served per route.
+type ServerMetrics struct {
+	requests *CounterVec
+	duration *HistogramVec
+}
+
+func NewServerMetrics(r *Registry) *ServerMetrics {
+	return &ServerMetrics{
+		requests: r.NewCounterVec("http_server_requests_total",
+			"Number of HTTP requests served, by route, method and status code.",
+			"route", "method", "code"),
+		duration: r.NewHistogramVec("http_server_request_duration_seconds",
+			"Latency of HTTP requests served, by route and method.",
+			nil, "route", "method"),
+	}
+}
+
+// Instrument records the requests handled by next under route, which should
+// be the pattern next is registered with rather than the request path, to
+// keep the number of series bounded.
+func (m *ServerMetrics) Instrument(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := httputil.NewStatusRecorder(w)
+
+		next.ServeHTTP(recorder, r)
+
+		m.duration.ObserveDuration(time.Since(start), route, r.Method)
+		m.requests.Inc(route, r.Method, strconv.Itoa(recorder.Status()))
+	})
+}
+
+// ClientMetrics times outbound HTTP calls per host. It implements
+// client.RequestObserver.
+type ClientMetrics struct {
+	duration *HistogramVec
+}
+
+func NewClientMetrics(r *Registry) *ClientMetrics {
+	return &ClientMetrics{
+		duration: r.NewHistogramVec("http_client_request_duration_seconds",
+			"Latency of outbound HTTP calls, by host, method and status code. Transport failures have status \"error\".",
+			nil, "host", "method", "status"),
+	}
+}
+
+func (m *ClientMetrics) ObserveRequest(host, method string, statusCode int, err error, duration time.Duration) {
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(statusCode)
+	}
+	m.duration.ObserveDuration(duration, host, method, status)
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); got != contentType {
+		t.Errorf("content type %q, want %q", got, contentType)
+	}
+	return rec.Body.String()
+}
+
+func TestCounterExposition(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("requests_total", "Requests by path.\nSee \\docs.", "path")
+
+	requests.Inc(`/a"b`)
+	requests.Add(2, "/line\nbreak")
+	requests.Add(0.5, `C:\tmp`)
+	requests.Add(-10, `C:\tmp`)
+
+	want := `# HELP requests_total Requests by path.\nSee \\docs.
+# TYPE requests_total counter
+requests_total{path="/a\"b"} 1
+requests_total{path="/line\nbreak"} 2
+requests_total{path="C:\\tmp"} 0.5
+`
+	if got := scrape(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHistogramExposition(t *testing.T) {
+	r := NewRegistry()
+	latency := r.NewHistogramVec("latency_seconds", "Latency.", []float64{1, 0.1, 0.5}, "route")
+
+	for _, value := range []float64{0.05, 0.1, 0.3, 0.7, 3} {
+		latency.Observe(value, "/a")
+	}
+	latency.Observe(0.2, "/b")
+
+	want := `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a",le="0.1"} 2
+latency_seconds_bucket{route="/a",le="0.5"} 3
+latency_seconds_bucket{route="/a",le="1"} 4
+latency_seconds_bucket{route="/a",le="+Inf"} 5
+latency_seconds_sum{route="/a"} 4.15
+latency_seconds_count{route="/a"} 5
+latency_seconds_bucket{route="/b",le="0.1"} 0
+latency_seconds_bucket{route="/b",le="0.5"} 1
+latency_seconds_bucket{route="/b",le="1"} 1
+latency_seconds_bucket{route="/b",le="+Inf"} 1
+latency_seconds_sum{route="/b"} 0.2
+latency_seconds_count{route="/b"} 1
+`
+	if got := scrape(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRegistryOrdersFamiliesByName(t *testing.T) {
+	r := NewRegistry()
+	r.NewGaugeFunc("zeta", "Last.", func() float64 { return 2 })
+	r.NewCounterFunc("alpha_total", "First.", func() float64 { return 1 })
+	r.NewCounterVec("middle_total", "Without samples.", "label")
+
+	want := `# HELP alpha_total First.
+# TYPE alpha_total counter
+alpha_total 1
+# HELP middle_total Without samples.
+# TYPE middle_total counter
+# HELP zeta Last.
+# TYPE zeta gauge
+zeta 2
+`
+	if got := scrape(t, r); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRegistryRejectsDuplicateNames(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounterVec("requests_total", "Requests.")
+
+	defer func() {
+		if recovered := recover(); recovered == nil || !strings.Contains(recovered.(string), "duplicate") {
+			t.Errorf("recovered %v, want a duplicate metric panic", recovered)
+		}
+	}()
+	r.NewGaugeFunc("requests_total", "Requests.", func() float64 { return 0 })
+}
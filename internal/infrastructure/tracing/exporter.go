@@ -0,0 +1,195 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Exporter receives every sampled span once it ends. Export is called from
+// the goroutine ending the span and must be safe for concurrent use. Export
+// failures must not affect the traced operation, so they are reported by
+// Shutdown instead.
+type Exporter interface {
+	Export(span SpanData)
+	Shutdown(ctx context.Context) error
+}
+
+// jsonLinesExporter writes one JSON document per span and keeps the first
+// write error.
+type jsonLinesExporter struct {
+	mu      sync.Mutex
+	encoder *json.Encoder
+	err     error
+	closer  io.Closer
+	format  func(SpanData) interface{}
+}
+
+func (e *jsonLinesExporter) Export(span SpanData) {
+	document := e.format(span)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.encoder.Encode(document); err != nil && e.err == nil {
+		e.err = err
+	}
+}
+
+func (e *jsonLinesExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.err
+	if e.closer != nil {
+		if closeErr := e.closer.Close(); err == nil {
+			err = closeErr
+		}
+		e.closer = nil
+	}
+	return err
+}
+
+// NewStdoutExporter writes every span to w as a single-line JSON object
+// meant to be read by people, e.g. next to JSON logs.
+func NewStdoutExporter(w io.Writer) Exporter {
+	return &jsonLinesExporter{
+		encoder: json.NewEncoder(w),
+		format:  formatSpan,
+	}
+}
+
+// NewOTLPFileExporter appends every span to the file at path as an OTLP/JSON
+// ExportTraceServiceRequest, one per line. This is the format written by the
+// OpenTelemetry Collector file exporter and read by its otlpjsonfile
+// receiver, so the file can be replayed into any OTLP backend.
+func NewOTLPFileExporter(path string) (Exporter, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonLinesExporter{
+		encoder: json.NewEncoder(file),
+		closer:  file,
+		format:  formatOTLP,
+	}, nil
+}
+
+var kindNames = map[SpanKind]string{
+	SpanKindInternal: "internal",
+	SpanKindServer:   "server",
+	SpanKindClient:   "client",
+}
+
+var statusNames = map[StatusCode]string{
+	StatusUnset: "unset",
+	StatusOK:    "ok",
+	StatusError: "error",
+}
+
+func formatSpan(span SpanData) interface{} {
+	attributes := make(map[string]interface{}, len(span.Attributes))
+	for _, attribute := range span.Attributes {
+		attributes[attribute.Key] = attribute.Value
+	}
+
+	document := map[string]interface{}{
+		"time":       span.Start.Format(time.RFC3339Nano),
+		"service":    span.ServiceName,
+		"name":       span.Name,
+		"kind":       kindNames[span.Kind],
+		"traceId":    span.SpanContext.TraceID.String(),
+		"spanId":     span.SpanContext.SpanID.String(),
+		"durationMs": float64(span.End.Sub(span.Start).Microseconds()) / 1000,
+		"status":     statusNames[span.Status],
+		"attributes": attributes,
+	}
+	if span.ParentSpanID.IsValid() {
+		document["parentSpanId"] = span.ParentSpanID.String()
+	}
+	if span.StatusMessage != "" {
+		document["statusMessage"] = span.StatusMessage
+	}
+	return document
+}
+
+// scopeName is the OTLP instrumentation scope of every exported span.
+const scopeName = "github.com/marcopaulosilva/poc_devin/internal/infrastructure/tracing"
+
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            struct {
+		Code    StatusCode `json:"code,omitempty"`
+		Message string     `json:"message,omitempty"`
+	} `json:"status"`
+}
+
+func formatOTLP(span SpanData) interface{} {
+	converted := otlpSpan{
+		TraceID:           span.SpanContext.TraceID.String(),
+		SpanID:            span.SpanContext.SpanID.String(),
+		Name:              span.Name,
+		Kind:              span.Kind,
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+	}
+	if span.ParentSpanID.IsValid() {
+		converted.ParentSpanID = span.ParentSpanID.String()
+	}
+	for _, attribute := range span.Attributes {
+		converted.Attributes = append(converted.Attributes, otlpAttribute(attribute))
+	}
+	converted.Status.Code = span.Status
+	converted.Status.Message = span.StatusMessage
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otlpKeyValue{otlpAttribute(String("service.name", span.ServiceName))},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]string{"name": scopeName},
+						"spans": []otlpSpan{converted},
+					},
+				},
+			},
+		},
+	}
+}
+
+// otlpAttribute encodes a as an OTLP AnyValue. The protobuf JSON mapping
+// encodes 64-bit integers as strings.
+func otlpAttribute(a Attribute) otlpKeyValue {
+	var value map[string]interface{}
+	switch v := a.Value.(type) {
+	case string:
+		value = map[string]interface{}{"stringValue": v}
+	case int64:
+		value = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+	case float64:
+		value = map[string]interface{}{"doubleValue": v}
+	case bool:
+		value = map[string]interface{}{"boolValue": v}
+	default:
+		value = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+	}
+	return otlpKeyValue{Key: a.Key, Value: value}
+}
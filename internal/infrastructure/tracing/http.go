@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/httputil"
+)
+
+// TraceParentHeader is the W3C Trace Context header.
+const TraceParentHeader = "traceparent"
+
+// Inject sets the traceparent header of the span carried by ctx on header.
+func Inject(ctx context.Context, header http.Header) {
+	if sc := SpanContextFromContext(ctx); sc.IsValid() {
+		header.Set(TraceParentHeader, sc.TraceParent())
+	}
+}
+
+// Extract returns a copy of ctx carrying the span context of the traceparent
+// header, if header has a valid one.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	value := header.Get(TraceParentHeader)
+	if value == "" {
+		return ctx
+	}
+	sc, err := ParseTraceParent(value)
+	if err != nil {
+		return ctx
+	}
+	return ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Handler traces every request served by next in a server span named after
+// the method and route, which should be the pattern next is registered with.
+// The span continues the trace of an incoming traceparent header.
+func (t *Tracer) Handler(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := Extract(r.Context(), r.Header)
+		ctx, span := t.Start(ctx, r.Method+" "+route,
+			WithSpanKind(SpanKindServer),
+			WithAttributes(
+				String("http.request.method", r.Method),
+				String("http.route", route),
+				String("url.path", r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		recorder := httputil.NewStatusRecorder(w)
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		span.SetAttributes(Int("http.response.status_code", recorder.Status()))
+		if recorder.Status() >= http.StatusInternalServerError {
+			span.SetStatus(StatusError, http.StatusText(recorder.Status()))
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package tracing
+
+import "testing"
+
+func TestParseTraceParent(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	tests := []struct {
+		name        string
+		value       string
+		wantErr     bool
+		wantSampled bool
+	}{
+		{name: "sampled", value: "00-" + traceID + "-" + spanID + "-01", wantSampled: true},
+		{name: "not sampled", value: "00-" + traceID + "-" + spanID + "-00"},
+		{name: "other flags", value: "00-" + traceID + "-" + spanID + "-03", wantSampled: true},
+		{name: "surrounding spaces", value: " 00-" + traceID + "-" + spanID + "-01 ", wantSampled: true},
+		{name: "future version", value: "01-" + traceID + "-" + spanID + "-01", wantSampled: true},
+		{name: "future version with more fields", value: "cc-" + traceID + "-" + spanID + "-01-extra", wantSampled: true},
+		{name: "empty", value: "", wantErr: true},
+		{name: "version ff", value: "ff-" + traceID + "-" + spanID + "-01", wantErr: true},
+		{name: "long version", value: "000-" + traceID + "-" + spanID + "-01", wantErr: true},
+		{name: "too few fields", value: "00-" + traceID + "-" + spanID, wantErr: true},
+		{name: "version 00 with more fields", value: "00-" + traceID + "-" + spanID + "-01-extra", wantErr: true},
+		{name: "uppercase trace ID", value: "00-4BF92F3577B34DA6A3CE929D0E0E4736-" + spanID + "-01", wantErr: true},
+		{name: "uppercase flags", value: "00-" + traceID + "-" + spanID + "-0A", wantErr: true},
+		{name: "short trace ID", value: "00-" + traceID[2:] + "-" + spanID + "-01", wantErr: true},
+		{name: "short span ID", value: "00-" + traceID + "-" + spanID[2:] + "-01", wantErr: true},
+		{name: "not hex", value: "00-" + traceID + "-" + spanID + "-0g", wantErr: true},
+		{name: "all-zero trace ID", value: "00-00000000000000000000000000000000-" + spanID + "-01", wantErr: true},
+		{name: "all-zero span ID", value: "00-" + traceID + "-0000000000000000-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, err := ParseTraceParent(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsed %+v, want an error", sc)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sc.TraceID.String() != traceID || sc.SpanID.String() != spanID || sc.Sampled != tt.wantSampled {
+				t.Errorf("parsed %s, %s, sampled %v", sc.TraceID, sc.SpanID, sc.Sampled)
+			}
+		})
+	}
+}
+
+func TestTraceParentRoundTrip(t *testing.T) {
+	for _, sampled := range []bool{true, false} {
+		sc := SpanContext{TraceID: newTraceID(), SpanID: newSpanID(), Sampled: sampled}
+
+		parsed, err := ParseTraceParent(sc.TraceParent())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != sc {
+			t.Errorf("parsed %+v from %q, want %+v", parsed, sc.TraceParent(), sc)
+		}
+	}
+}
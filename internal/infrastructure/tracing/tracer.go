@@ -0,0 +1,252 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultFile is the file the otlp-file exporter appends to by default.
+const DefaultFile = "traces.jsonl"
+
+// SpanKind uses the OTLP enum values.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// StatusCode uses the OTLP enum values.
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Attribute is a key-value pair attached to a span. Values are strings,
+// int64s, float64s or bools; use the constructors below.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+func Float64(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Tracer starts spans and hands the sampled ones to its exporter once they
+// end.
+type Tracer struct {
+	serviceName string
+	exporter    Exporter
+}
+
+// NewTracer returns a tracer for serviceName. With a nil exporter spans are
+// still created and propagated but never sampled, so downstream services do
+// not record them either.
+func NewTracer(serviceName string, exporter Exporter) *Tracer {
+	return &Tracer{
+		serviceName: serviceName,
+		exporter:    exporter,
+	}
+}
+
+// New builds the tracer selected by exporter: "none" (the default),
+// "stdout", or "otlp-file", which appends to file ("traces.jsonl" by
+// default). It returns a tracer that exports nothing along with the error
+// when the exporter is unknown or the file cannot be opened.
+func New(serviceName, exporter, file string) (*Tracer, error) {
+	switch strings.ToLower(exporter) {
+	case "", "none":
+		return NewTracer(serviceName, nil), nil
+	case "stdout":
+		return NewTracer(serviceName, NewStdoutExporter(os.Stdout)), nil
+	case "otlp-file":
+		if file == "" {
+			file = DefaultFile
+		}
+		fileExporter, err := NewOTLPFileExporter(file)
+		if err != nil {
+			return NewTracer(serviceName, nil), err
+		}
+		return NewTracer(serviceName, fileExporter), nil
+	default:
+		return NewTracer(serviceName, nil), fmt.Errorf("unknown tracing exporter %q", exporter)
+	}
+}
+
+// Shutdown flushes and closes the exporter.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t.exporter == nil {
+		return nil
+	}
+	return t.exporter.Shutdown(ctx)
+}
+
+// StartOption customizes a span started by Tracer.Start or Start.
+type StartOption func(*Span)
+
+func WithSpanKind(kind SpanKind) StartOption {
+	return func(s *Span) {
+		s.kind = kind
+	}
+}
+
+func WithAttributes(attributes ...Attribute) StartOption {
+	return func(s *Span) {
+		s.attributes = append(s.attributes, attributes...)
+	}
+}
+
+// Start starts a span named name as a child of the span or remote span
+// context carried by ctx, or as the root of a new trace. The returned context
+// carries the new span. Callers must call End on it.
+func (t *Tracer) Start(ctx context.Context, name string, options ...StartOption) (context.Context, *Span) {
+	span := &Span{
+		tracer: t,
+		name:   name,
+		kind:   SpanKindInternal,
+		start:  time.Now(),
+	}
+
+	if parent := SpanContextFromContext(ctx); parent.IsValid() {
+		span.parentID = parent.SpanID
+		span.spanContext = SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled}
+	} else {
+		span.spanContext = SpanContext{TraceID: newTraceID(), Sampled: t.exporter != nil}
+	}
+	span.spanContext.SpanID = newSpanID()
+
+	for _, option := range options {
+		option(span)
+	}
+	return ContextWithSpan(ctx, span), span
+}
+
+// Start starts a child of the span carried by ctx with that span's tracer.
+// When ctx carries no span nothing is traced and the returned span is nil,
+// which is safe to use. Instrumented code that has no tracer of its own, such
+// as repositories, uses it so that it is traced only as part of a trace.
+func Start(ctx context.Context, name string, options ...StartOption) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	return parent.tracer.Start(ctx, name, options...)
+}
+
+// Span is an operation of a trace. A nil *Span is a valid span that records
+// nothing.
+type Span struct {
+	tracer      *Tracer
+	name        string
+	kind        SpanKind
+	spanContext SpanContext
+	parentID    SpanID
+	start       time.Time
+
+	mu            sync.Mutex
+	attributes    []Attribute
+	status        StatusCode
+	statusMessage string
+	end           time.Time
+}
+
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.spanContext
+}
+
+func (s *Span) SetAttributes(attributes ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attributes = append(s.attributes, attributes...)
+}
+
+func (s *Span) SetStatus(code StatusCode, message string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = code
+	s.statusMessage = message
+}
+
+// RecordError marks the span as failed with err. A nil err is ignored.
+func (s *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.SetStatus(StatusError, err.Error())
+}
+
+// End finishes the span and exports it when it is sampled. Calls after the
+// first are ignored.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if !s.end.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.end = time.Now()
+	data := SpanData{
+		ServiceName:   s.tracer.serviceName,
+		Name:          s.name,
+		Kind:          s.kind,
+		SpanContext:   s.spanContext,
+		ParentSpanID:  s.parentID,
+		Start:         s.start,
+		End:           s.end,
+		Attributes:    append([]Attribute(nil), s.attributes...),
+		Status:        s.status,
+		StatusMessage: s.statusMessage,
+	}
+	s.mu.Unlock()
+
+	if s.spanContext.Sampled && s.tracer.exporter != nil {
+		s.tracer.exporter.Export(data)
+	}
+}
+
+// SpanData is a finished span as handed to an Exporter.
+type SpanData struct {
+	ServiceName   string
+	Name          string
+	Kind          SpanKind
+	SpanContext   SpanContext
+	ParentSpanID  SpanID
+	Start         time.Time
+	End           time.Time
+	Attributes    []Attribute
+	Status        StatusCode
+	StatusMessage string
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+// ChampionPathPrefix is the path GetChampion is mounted on.
+const ChampionPathPrefix = "/api/champions/"
+
+type ChampionHandler struct {
+	championUseCase usecases.ChampionUseCase
+	logger          logger.Logger
+}
+
+func NewChampionHandler(championUseCase usecases.ChampionUseCase, logger logger.Logger) *ChampionHandler {
+	return &ChampionHandler{
+		championUseCase: championUseCase,
+		logger:          logger,
+	}
+}
+
+// GetChampion serves /api/champions/{id}, where id is the Data Dragon ID
+// ("Kassadin") or numeric key ("38"), with an optional ?locale=pt_BR. The
+// rank is the champion's position in the movement speed ranking.
+func (h *ChampionHandler) GetChampion(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, ChampionPathPrefix)
+	h.logger.Info("API request received: Get champion %s", id)
+
+	if id == "" || strings.Contains(id, "/") {
+		writeError(w, r, fmt.Errorf("%w: champion %q", usecases.ErrNotFound, id))
+		return
+	}
+
+	ranked, err := h.championUseCase.GetChampion(r.Context(), id, usecases.RankOptions{
+		Stat:    entities.StatMoveSpeed,
+		Order:   entities.SortDescending,
+		Ranking: entities.RankingCompetition,
+		Locale:  r.URL.Query().Get("locale"),
+	})
+	if err != nil {
+		if errors.Is(err, usecases.ErrNotFound) {
+			h.logger.Info("Champion %s not found", id)
+		} else {
+			h.logger.Error("Failed to get champion %s: %v", id, err)
+		}
+		writeError(w, r, err)
+		return
+	}
+
+	champion := ranked.Champion
+	response := struct {
+		Version       string                 `json:"version"`
+		Locale        string                 `json:"locale"`
+		Rank          int                    `json:"rank"`
+		ID            string                 `json:"id"`
+		Key           string                 `json:"key"`
+		Name          string                 `json:"name"`
+		Title         string                 `json:"title"`
+		MovementSpeed float64                `json:"movementSpeed"`
+		Stats         entities.ChampionStats `json:"stats"`
+	}{
+		Version:       champion.Version,
+		Locale:        champion.Locale,
+		Rank:          ranked.Rank,
+		ID:            champion.ID,
+		Key:           champion.Key,
+		Name:          champion.Name,
+		Title:         champion.Title,
+		MovementSpeed: champion.MovementSpeed,
+		Stats:         champion.Stats,
+	}
+
+	jsonResponse, err := json.Marshal(response)
+	if err != nil {
+		h.logger.Error("Failed to marshal response: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.logger.Success("Successfully returned champion %s", champion.ID)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonResponse)
+}
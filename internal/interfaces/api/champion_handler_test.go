@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+var testChampions = []entities.Champion{
+	{ID: "Ahri", Key: "103", Name: "Ahri", Title: "the Nine-Tailed Fox", Tags: []string{"Mage", "Assassin"}, MovementSpeed: 330, Stats: entities.ChampionStats{MoveSpeed: 330}, Version: "14.1.1"},
+	{ID: "Kassadin", Key: "38", Name: "Kassadin", Title: "the Void Walker", Tags: []string{"Assassin", "Mage"}, MovementSpeed: 335, Stats: entities.ChampionStats{MoveSpeed: 335}, Version: "14.1.1"},
+	{ID: "MonkeyKing", Key: "62", Name: "Wukong", Title: "the Monkey King", Tags: []string{"Fighter", "Tank"}, MovementSpeed: 340, Stats: entities.ChampionStats{MoveSpeed: 340}, Version: "14.1.1"},
+}
+
+func TestGetChampion(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantID     string
+		wantRank   int
+		wantLocale string
+		// wantLookup is false when the path is rejected before the
+		// champions are fetched.
+		wantLookup bool
+	}{
+		{name: "id", path: "/api/champions/Kassadin", wantID: "Kassadin", wantRank: 2, wantLookup: true},
+		{name: "lower case id", path: "/api/champions/kassadin", wantID: "Kassadin", wantRank: 2, wantLookup: true},
+		{name: "upper case id", path: "/api/champions/MONKEYKING", wantID: "MonkeyKing", wantRank: 1, wantLookup: true},
+		{name: "key", path: "/api/champions/38", wantID: "Kassadin", wantRank: 2, wantLookup: true},
+		{name: "other key", path: "/api/champions/103", wantID: "Ahri", wantRank: 3, wantLookup: true},
+		{name: "locale", path: "/api/champions/Ahri?locale=ko_KR", wantID: "Ahri", wantRank: 3, wantLocale: "ko_KR", wantLookup: true},
+		{name: "name is not an id", path: "/api/champions/Wukong", wantLookup: true},
+		{name: "unknown id", path: "/api/champions/Teemo", wantLookup: true},
+		{name: "unknown key", path: "/api/champions/17", wantLookup: true},
+		{name: "empty id", path: "/api/champions/"},
+		{name: "extra segment", path: "/api/champions/Kassadin/stats"},
+		{name: "trailing slash", path: "/api/champions/Kassadin/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &championRepository{champions: testChampions}
+			handler := NewChampionHandler(usecases.NewChampionUseCase(repo), logger.NewJSONLogger(io.Discard, logger.LevelError))
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			handler.GetChampion(rec, req)
+
+			if fetched := repo.locale != ""; fetched != tt.wantLookup {
+				t.Errorf("champions fetched: %t, want %t", fetched, tt.wantLookup)
+			}
+
+			if tt.wantID == "" {
+				if rec.Code != http.StatusNotFound {
+					t.Fatalf("status %d, want %d: %s", rec.Code, http.StatusNotFound, rec.Body)
+				}
+				if got := rec.Header().Get("Content-Type"); got != problemContentType {
+					t.Errorf("Content-Type %q, want %q", got, problemContentType)
+				}
+				var problem Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+					t.Fatalf("decoding problem: %v", err)
+				}
+				if problem.Code != CodeNotFound || problem.Status != http.StatusNotFound || problem.Instance != req.URL.Path {
+					t.Errorf("problem %+v, want code %s, status %d and instance %s", problem, CodeNotFound, http.StatusNotFound, req.URL.Path)
+				}
+				return
+			}
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			var body struct {
+				Version       string  `json:"version"`
+				Locale        string  `json:"locale"`
+				Rank          int     `json:"rank"`
+				ID            string  `json:"id"`
+				MovementSpeed float64 `json:"movementSpeed"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			wantLocale := tt.wantLocale
+			if wantLocale == "" {
+				wantLocale = entities.DefaultLocale
+			}
+			if body.ID != tt.wantID || body.Rank != tt.wantRank || body.Locale != wantLocale || body.Version != "14.1.1" {
+				t.Errorf("got %+v, want id %s, rank %d and locale %s", body, tt.wantID, tt.wantRank, wantLocale)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+)
+
+// MaxPageLimit bounds the limit parameter of the champion list.
+const MaxPageLimit = 200
+
+type championResponse struct {
+	Rank          int                    `json:"rank"`
+	ID            string                 `json:"id"`
+	Name          string                 `json:"name"`
+	Title         string                 `json:"title"`
+	Tags          []string               `json:"tags"`
+	MovementSpeed float64                `json:"movementSpeed"`
+	Stats         entities.ChampionStats `json:"stats"`
+}
+
+// championResponseFields are the names accepted by the fields parameter,
+// matching the JSON names of championResponse.
+var championResponseFields = []string{"rank", "id", "name", "title", "tags", "movementSpeed", "stats"}
+
+func newChampionResponse(ranked entities.RankedChampion) championResponse {
+	champion := ranked.Champion
+	tags := champion.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return championResponse{
+		Rank:          ranked.Rank,
+		ID:            champion.ID,
+		Name:          champion.Name,
+		Title:         champion.Title,
+		Tags:          tags,
+		MovementSpeed: champion.MovementSpeed,
+		Stats:         champion.Stats,
+	}
+}
+
+// project keeps only the given fields, which must have been checked by
+// parseFields.
+func (c championResponse) project(fields []string) map[string]interface{} {
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "rank":
+			projected[field] = c.Rank
+		case "id":
+			projected[field] = c.ID
+		case "name":
+			projected[field] = c.Name
+		case "title":
+			projected[field] = c.Title
+		case "tags":
+			projected[field] = c.Tags
+		case "movementSpeed":
+			projected[field] = c.MovementSpeed
+		case "stats":
+			projected[field] = c.Stats
+		}
+	}
+	return projected
+}
+
+// listQueryParams are the query parameters accepted by the champion list.
+var listQueryParams = []string{"locale", "minSpeed", "maxSpeed", "name", "tags", "limit", "offset", "fields"}
+
+type listParams struct {
+	filter usecases.ChampionFilter
+	limit  int
+	offset int
+	fields []string
+}
+
+func parseListParams(query url.Values) (listParams, error) {
+	var params listParams
+	var err error
+
+	if err := checkQueryParams(query); err != nil {
+		return params, err
+	}
+
+	if params.filter.MinMovementSpeed, err = parseOptionalFloat(query, "minSpeed"); err != nil {
+		return params, err
+	}
+	if params.filter.MaxMovementSpeed, err = parseOptionalFloat(query, "maxSpeed"); err != nil {
+		return params, err
+	}
+	params.filter.NamePrefix = query.Get("name")
+	params.filter.Tags = splitList(query["tags"])
+	if err := params.filter.Validate(); err != nil {
+		return params, err
+	}
+
+	if value := query.Get("limit"); value != "" {
+		params.limit, err = strconv.Atoi(value)
+		if err != nil || params.limit < 1 || params.limit > MaxPageLimit {
+			return params, fmt.Errorf("%w: limit must be an integer between 1 and %d", usecases.ErrInvalidParameter, MaxPageLimit)
+		}
+	}
+	if value := query.Get("offset"); value != "" {
+		params.offset, err = strconv.Atoi(value)
+		if err != nil || params.offset < 0 {
+			return params, fmt.Errorf("%w: offset must be a non-negative integer", usecases.ErrInvalidParameter)
+		}
+	}
+
+	params.fields, err = parseFields(splitList(query["fields"]))
+	return params, err
+}
+
+// checkQueryParams rejects parameters the list does not know, so that a
+// misspelled filter does not silently return every champion.
+func checkQueryParams(query url.Values) error {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		known := false
+		for _, candidate := range listQueryParams {
+			if name == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown parameter %q, expected one of %s", usecases.ErrInvalidParameter, name, strings.Join(listQueryParams, ", "))
+		}
+	}
+	return nil
+}
+
+func parseOptionalFloat(query url.Values, name string) (*float64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s must be a number", usecases.ErrInvalidParameter, name)
+	}
+	return &parsed, nil
+}
+
+func parseFields(fields []string) ([]string, error) {
+	for _, field := range fields {
+		known := false
+		for _, candidate := range championResponseFields {
+			if field == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown field %q, expected one of %s", usecases.ErrInvalidParameter, field, strings.Join(championResponseFields, ", "))
+		}
+	}
+	return fields, nil
+}
+
+// splitList accepts both repeated parameters and comma separated values.
+func splitList(values []string) []string {
+	var items []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// paginate returns the page of ranked starting at offset. A limit of zero
+// returns everything after offset.
+func paginate(ranked []entities.RankedChampion, offset, limit int) []entities.RankedChampion {
+	if offset >= len(ranked) {
+		return []entities.RankedChampion{}
+	}
+	ranked = ranked[offset:]
+	if limit > 0 && limit < len(ranked) {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+type listResponse struct {
+	Count      int                          `json:"count"`
+	Total      int                          `json:"total"`
+	Offset     int                          `json:"offset"`
+	Limit      int                          `json:"limit"`
+	NextOffset *int                         `json:"nextOffset"`
+	Champions  []map[string]json.RawMessage `json:"champions"`
+}
+
+// getChampionList serves query from the movement speed list of
+// testChampions, ranked MonkeyKing, Kassadin, Ahri.
+func getChampionList(t *testing.T, query string) (*httptest.ResponseRecorder, *championRepository) {
+	t.Helper()
+	repo := &championRepository{champions: testChampions}
+	handler := NewMovementSpeedHandler(usecases.NewChampionUseCase(repo), logger.NewJSONLogger(io.Discard, logger.LevelError))
+
+	rec := httptest.NewRecorder()
+	handler.GetChampionsByMovementSpeed(rec, httptest.NewRequest(http.MethodGet, "/api/champions/movement-speed?"+query, nil))
+	return rec, repo
+}
+
+func TestGetChampionsByMovementSpeedInvalidParams(t *testing.T) {
+	queries := []string{
+		"limit=0",
+		"limit=-1",
+		"limit=201",
+		"limit=ten",
+		"offset=-1",
+		"offset=first",
+		"minSpeed=fast",
+		"minSpeed=340&maxSpeed=330",
+		"fields=speed",
+		"fields=id,Name",
+		"minspeed=340",
+		"speed=340",
+		"tag=Mage",
+	}
+
+	for _, query := range queries {
+		t.Run(query, func(t *testing.T) {
+			rec, repo := getChampionList(t, query)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status %d, want %d: %s", rec.Code, http.StatusBadRequest, rec.Body)
+			}
+			var problem Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil || problem.Code != CodeInvalidParameter {
+				t.Errorf("problem %+v (%v), want code %s", problem, err, CodeInvalidParameter)
+			}
+			if repo.locale != "" {
+				t.Errorf("champions fetched despite the invalid request")
+			}
+		})
+	}
+}
+
+func TestGetChampionsByMovementSpeedPage(t *testing.T) {
+	next := func(offset int) *int { return &offset }
+
+	tests := []struct {
+		query     string
+		wantIDs   []string
+		wantTotal int
+		wantNext  *int
+	}{
+		{query: "", wantIDs: []string{"MonkeyKing", "Kassadin", "Ahri"}, wantTotal: 3},
+		{query: "limit=200", wantIDs: []string{"MonkeyKing", "Kassadin", "Ahri"}, wantTotal: 3},
+		{query: "limit=2", wantIDs: []string{"MonkeyKing", "Kassadin"}, wantTotal: 3, wantNext: next(2)},
+		{query: "limit=1&offset=1", wantIDs: []string{"Kassadin"}, wantTotal: 3, wantNext: next(2)},
+		{query: "limit=2&offset=2", wantIDs: []string{"Ahri"}, wantTotal: 3},
+		{query: "offset=3", wantIDs: []string{}, wantTotal: 3},
+		{query: "offset=50&limit=10", wantIDs: []string{}, wantTotal: 3},
+		{query: "maxSpeed=", wantIDs: []string{"MonkeyKing", "Kassadin", "Ahri"}, wantTotal: 3},
+		{query: "minSpeed=335", wantIDs: []string{"MonkeyKing", "Kassadin"}, wantTotal: 2},
+		{query: "minSpeed=330&maxSpeed=335", wantIDs: []string{"Kassadin", "Ahri"}, wantTotal: 2},
+		{query: "name=wu", wantIDs: []string{"MonkeyKing"}, wantTotal: 1},
+		{query: "name=monkey", wantIDs: []string{"MonkeyKing"}, wantTotal: 1},
+		{query: "tags=assassin,MAGE", wantIDs: []string{"Kassadin", "Ahri"}, wantTotal: 2},
+		{query: "tags=Assassin&tags=Tank", wantIDs: []string{}, wantTotal: 0},
+		{query: "tags=Mage&limit=1", wantIDs: []string{"Kassadin"}, wantTotal: 2, wantNext: next(1)},
+		{query: "minSpeed=400", wantIDs: []string{}, wantTotal: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec, _ := getChampionList(t, tt.query)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+
+			var body listResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if body.Champions == nil {
+				t.Fatalf("champions is %s, want an array", rec.Body)
+			}
+			ids := make([]string, 0, len(body.Champions))
+			for _, champion := range body.Champions {
+				var id string
+				json.Unmarshal(champion["id"], &id)
+				ids = append(ids, id)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("champions %v, want %v", ids, tt.wantIDs)
+			}
+			if body.Count != len(tt.wantIDs) || body.Total != tt.wantTotal {
+				t.Errorf("count %d and total %d, want %d and %d", body.Count, body.Total, len(tt.wantIDs), tt.wantTotal)
+			}
+			if !reflect.DeepEqual(body.NextOffset, tt.wantNext) {
+				t.Errorf("nextOffset %v, want %v", body.NextOffset, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestGetChampionsByMovementSpeedFields(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantFields []string
+	}{
+		{query: "", wantFields: []string{"id", "movementSpeed", "name", "rank", "stats", "tags", "title"}},
+		{query: "fields=id", wantFields: []string{"id"}},
+		{query: "fields=rank,name,movementSpeed", wantFields: []string{"movementSpeed", "name", "rank"}},
+		{query: "fields=id&fields=tags", wantFields: []string{"id", "tags"}},
+		{query: "fields=id,+id,,stats", wantFields: []string{"id", "stats"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec, _ := getChampionList(t, tt.query)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+
+			var body listResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+			if len(body.Champions) != len(testChampions) {
+				t.Fatalf("%d champions, want %d", len(body.Champions), len(testChampions))
+			}
+			for _, champion := range body.Champions {
+				fields := make([]string, 0, len(champion))
+				for field := range champion {
+					fields = append(fields, field)
+				}
+				sort.Strings(fields)
+				if !reflect.DeepEqual(fields, tt.wantFields) {
+					t.Errorf("fields %v, want %v", fields, tt.wantFields)
+				}
+			}
+		})
+	}
+}
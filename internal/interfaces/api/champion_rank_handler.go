@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+// ChampionRankPath is the path GetChampionRanking is mounted on.
+const ChampionRankPath = "/api/champions/rank"
+
+type ChampionRankHandler struct {
+	championUseCase usecases.ChampionUseCase
+	logger          logger.Logger
+}
+
+func NewChampionRankHandler(championUseCase usecases.ChampionUseCase, logger logger.Logger) *ChampionRankHandler {
+	return &ChampionRankHandler{
+		championUseCase: championUseCase,
+		logger:          logger,
+	}
+}
+
+// GetChampionRanking serves /api/champions/rank?stat=armor&order=desc&level=9&ranking=dense.
+// Only stat is required.
+func (h *ChampionRankHandler) GetChampionRanking(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("API request received: Rank champions by stat")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	if query.Get("stat") == "" {
+		writeError(w, r, fmt.Errorf("%w: missing required parameter stat", usecases.ErrInvalidParameter))
+		return
+	}
+
+	options := usecases.RankOptions{
+		Stat:    entities.Stat(query.Get("stat")),
+		Order:   entities.SortOrder(query.Get("order")),
+		Ranking: entities.RankingMethod(query.Get("ranking")),
+	}
+	if value := query.Get("level"); value != "" {
+		level, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("%w: level must be an integer", usecases.ErrInvalidParameter))
+			return
+		}
+		// Normalize reads a zero level as unset, so an explicit one is
+		// rejected here.
+		if level < entities.MinChampionLevel {
+			writeError(w, r, fmt.Errorf("%w: level must be between %d and %d", usecases.ErrInvalidParameter, entities.MinChampionLevel, entities.MaxChampionLevel))
+			return
+		}
+		options.Level = level
+	}
+
+	options, err := options.Normalize()
+	if err != nil {
+		h.logger.Error("Invalid ranking request: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	ranked, err := h.championUseCase.RankChampions(r.Context(), options)
+	if err != nil {
+		h.logger.Error("Failed to rank champions: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	type RankedChampionResponse struct {
+		Rank  int                    `json:"rank"`
+		ID    string                 `json:"id"`
+		Name  string                 `json:"name"`
+		Title string                 `json:"title"`
+		Value float64                `json:"value"`
+		Stats entities.ChampionStats `json:"stats"`
+	}
+
+	response := struct {
+		Version   string                   `json:"version"`
+		Stat      string                   `json:"stat"`
+		Order     string                   `json:"order"`
+		Level     int                      `json:"level"`
+		Ranking   string                   `json:"ranking"`
+		Count     int                      `json:"count"`
+		Champions []RankedChampionResponse `json:"champions"`
+	}{
+		Stat:      string(options.Stat),
+		Order:     string(options.Order),
+		Level:     options.Level,
+		Ranking:   string(options.Ranking),
+		Count:     len(ranked),
+		Champions: make([]RankedChampionResponse, 0, len(ranked)),
+	}
+	if len(ranked) > 0 {
+		response.Version = ranked[0].Champion.Version
+	}
+
+	for _, champion := range ranked {
+		response.Champions = append(response.Champions, RankedChampionResponse{
+			Rank:  champion.Rank,
+			ID:    champion.Champion.ID,
+			Name:  champion.Champion.Name,
+			Title: champion.Champion.Title,
+			Value: champion.Value,
+			Stats: champion.Champion.Stats,
+		})
+	}
+
+	jsonResponse, err := json.Marshal(response)
+	if err != nil {
+		h.logger.Error("Failed to marshal response: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.logger.Success("Successfully ranked %d champions by %s", len(ranked), options.Stat)
+	w.Write(jsonResponse)
+}
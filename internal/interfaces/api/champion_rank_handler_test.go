@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+// rankUseCase records the options it ranks with.
+type rankUseCase struct {
+	usecases.ChampionUseCase
+	options *usecases.RankOptions
+}
+
+func (u *rankUseCase) RankChampions(ctx context.Context, options usecases.RankOptions) ([]entities.RankedChampion, error) {
+	u.options = &options
+	return nil, nil
+}
+
+func TestGetChampionRankingLevel(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantCode  int
+		wantLevel int
+	}{
+		{query: "stat=armor", wantCode: http.StatusOK, wantLevel: 1},
+		{query: "stat=armor&level=", wantCode: http.StatusOK, wantLevel: 1},
+		{query: "stat=armor&level=1", wantCode: http.StatusOK, wantLevel: 1},
+		{query: "stat=armor&level=18", wantCode: http.StatusOK, wantLevel: 18},
+		{query: "stat=armor&level=0", wantCode: http.StatusBadRequest},
+		{query: "stat=armor&level=-3", wantCode: http.StatusBadRequest},
+		{query: "stat=armor&level=19", wantCode: http.StatusBadRequest},
+		{query: "stat=armor&level=two", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			useCase := &rankUseCase{}
+			handler := NewChampionRankHandler(useCase, logger.NewJSONLogger(io.Discard, logger.LevelError))
+
+			rec := httptest.NewRecorder()
+			handler.GetChampionRanking(rec, httptest.NewRequest(http.MethodGet, "/api/champions/rank?"+tt.query, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if tt.wantCode != http.StatusOK {
+				var problem Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil || problem.Code != CodeInvalidParameter {
+					t.Errorf("problem %+v (%v), want code %s", problem, err, CodeInvalidParameter)
+				}
+				if useCase.options != nil {
+					t.Errorf("ranked with %+v despite the invalid level", *useCase.options)
+				}
+				return
+			}
+			if useCase.options == nil || useCase.options.Level != tt.wantLevel {
+				t.Errorf("ranked with %+v, want level %d", useCase.options, tt.wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxTrackedResources bounds the number of request URIs whose Last-Modified
+// time is remembered.
+const maxTrackedResources = 1024
+
+// responseValidators remembers, per request URI and Content-Language, the
+// current ETag and the time it was first served so that unchanged responses
+// keep a stable Last-Modified value.
+type responseValidators struct {
+	mu        sync.Mutex
+	resources map[string]responseValidator
+}
+
+type responseValidator struct {
+	etag         string
+	lastModified time.Time
+}
+
+func newResponseValidators() *responseValidators {
+	return &responseValidators{resources: make(map[string]responseValidator)}
+}
+
+// writeConditional writes body as a JSON response with a strong ETag derived
+// from the body and the Data Dragon version. It answers 304 Not Modified when
+// the request's If-None-Match or If-Modified-Since shows the client already
+// has this representation.
+func (v *responseValidators) writeConditional(w http.ResponseWriter, r *http.Request, body []byte, version string) {
+	etag := computeETag(body, version)
+	lastModified := v.lastModified(r.URL.RequestURI()+"\x00"+w.Header().Get("Content-Language"), etag)
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if notModified(r, etag, lastModified) {
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Write(body)
+}
+
+func (v *responseValidators) lastModified(resource, etag string) time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if current, ok := v.resources[resource]; ok && current.etag == etag {
+		return current.lastModified
+	}
+
+	if len(v.resources) >= maxTrackedResources {
+		v.resources = make(map[string]responseValidator)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	v.resources[resource] = responseValidator{etag: etag, lastModified: now}
+	return now
+}
+
+func computeETag(body []byte, version string) string {
+	hash := sha256.New()
+	hash.Write([]byte(version))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return `"` + hex.EncodeToString(hash.Sum(nil)) + `"`
+}
+
+// notModified evaluates the conditional request headers as described in
+// RFC 9110: If-None-Match takes precedence over If-Modified-Since.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if header := r.Header.Get("If-Modified-Since"); header != "" {
+		since, err := http.ParseTime(header)
+		if err == nil && !lastModified.After(since) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func serveConditional(v *responseValidators, method string, header http.Header, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/api/champions/movement-speed", nil)
+	for key, values := range header {
+		r.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "application/json")
+	v.writeConditional(w, r, []byte(body), "15.7.1")
+	return w
+}
+
+func TestWriteConditional(t *testing.T) {
+	const body = `{"champions":[]}`
+	v := newResponseValidators()
+	first := serveConditional(v, http.MethodGet, nil, body)
+	etag := first.Header().Get("ETag")
+	lastModified := first.Header().Get("Last-Modified")
+	if first.Code != http.StatusOK || etag == "" || lastModified == "" {
+		t.Fatalf("first response %d with ETag %q and Last-Modified %q", first.Code, etag, lastModified)
+	}
+
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+	after := modified.Add(time.Hour).Format(http.TimeFormat)
+
+	tests := []struct {
+		name       string
+		method     string
+		header     http.Header
+		wantStatus int
+	}{
+		{name: "no validators", wantStatus: http.StatusOK},
+		{name: "matching ETag", header: http.Header{"If-None-Match": {etag}}, wantStatus: http.StatusNotModified},
+		{name: "HEAD with matching ETag", method: http.MethodHead, header: http.Header{"If-None-Match": {etag}}, wantStatus: http.StatusNotModified},
+		{name: "other ETag", header: http.Header{"If-None-Match": {`"other"`}}, wantStatus: http.StatusOK},
+		{name: "ETag in a list", header: http.Header{"If-None-Match": {`"other", ` + etag}}, wantStatus: http.StatusNotModified},
+		{name: "any ETag", header: http.Header{"If-None-Match": {"*"}}, wantStatus: http.StatusNotModified},
+		{name: "weak ETag compares weakly", header: http.Header{"If-None-Match": {"W/" + etag}}, wantStatus: http.StatusNotModified},
+		{name: "weak other ETag", header: http.Header{"If-None-Match": {`W/"other"`}}, wantStatus: http.StatusOK},
+		{name: "unquoted ETag", header: http.Header{"If-None-Match": {etag[1 : len(etag)-1]}}, wantStatus: http.StatusOK},
+		{name: "not modified since", header: http.Header{"If-Modified-Since": {lastModified}}, wantStatus: http.StatusNotModified},
+		{name: "modified since", header: http.Header{"If-Modified-Since": {before}}, wantStatus: http.StatusOK},
+		{name: "malformed date", header: http.Header{"If-Modified-Since": {"yesterday"}}, wantStatus: http.StatusOK},
+		{
+			name:       "If-None-Match takes precedence over a matching date",
+			header:     http.Header{"If-None-Match": {`"other"`}, "If-Modified-Since": {after}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "If-None-Match takes precedence over an older date",
+			header:     http.Header{"If-None-Match": {etag}, "If-Modified-Since": {before}},
+			wantStatus: http.StatusNotModified,
+		},
+		{name: "POST is never conditional", method: http.MethodPost, header: http.Header{"If-None-Match": {etag}}, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			w := serveConditional(v, method, tt.header, body)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag %q, want %q", got, etag)
+			}
+			if got := w.Header().Get("Last-Modified"); got != lastModified {
+				t.Errorf("Last-Modified %q, want %q", got, lastModified)
+			}
+			if tt.wantStatus == http.StatusNotModified {
+				if w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+					t.Errorf("304 with body %q and Content-Type %q", w.Body, w.Header().Get("Content-Type"))
+				}
+			} else if w.Body.String() != body {
+				t.Errorf("body %q, want %q", w.Body, body)
+			}
+		})
+	}
+}
+
+func TestWriteConditionalAfterBodyChanges(t *testing.T) {
+	v := newResponseValidators()
+	old := serveConditional(v, http.MethodGet, nil, `{"count":1}`)
+	oldETag := old.Header().Get("ETag")
+
+	// Pretend the first body was served an hour ago, so that a new
+	// Last-Modified is told apart within the same second.
+	for resource, validator := range v.resources {
+		validator.lastModified = validator.lastModified.Add(-time.Hour)
+		v.resources[resource] = validator
+	}
+	oldModified := serveConditional(v, http.MethodGet, nil, `{"count":1}`).Header().Get("Last-Modified")
+
+	w := serveConditional(v, http.MethodGet, http.Header{
+		"If-None-Match":     {oldETag},
+		"If-Modified-Since": {oldModified},
+	}, `{"count":2}`)
+
+	if w.Code != http.StatusOK || w.Body.String() != `{"count":2}` {
+		t.Fatalf("got %d %q, want the new body", w.Code, w.Body)
+	}
+	if etag := w.Header().Get("ETag"); etag == "" || etag == oldETag {
+		t.Errorf("ETag %q, want a new one", etag)
+	}
+	oldTime, _ := http.ParseTime(oldModified)
+	newTime, err := http.ParseTime(w.Header().Get("Last-Modified"))
+	if err != nil || !newTime.After(oldTime) {
+		t.Errorf("Last-Modified %q, want later than %q", w.Header().Get("Last-Modified"), oldModified)
+	}
+}
+
+func TestComputeETagDependsOnVersion(t *testing.T) {
+	if computeETag([]byte("{}"), "15.7.1") == computeETag([]byte("{}"), "15.8.1") {
+		t.Error("the same body of two patches has the same ETag")
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
+)
+
+// ErrorCode is the stable, machine readable code of a Problem. Clients should
+// switch on it rather than on the human readable title and detail.
+type ErrorCode string
+
+const (
+	CodeInvalidParameter    ErrorCode = "invalid_parameter"
+	CodeNotFound            ErrorCode = "not_found"
+	CodeUpstreamUnavailable ErrorCode = "upstream_unavailable"
+	CodeClientClosedRequest ErrorCode = "client_closed_request"
+	CodeShuttingDown        ErrorCode = "shutting_down"
+	CodeInternal            ErrorCode = "internal_error"
+)
+
+const problemContentType = "application/problem+json"
+
+// StatusClientClosedRequest is the non-standard status, borrowed from nginx,
+// of requests abandoned by their client. It is only seen in logs and metrics.
+const StatusClientClosedRequest = 499
+
+// Problem is an RFC 7807 problem details object. Code and RequestID are
+// extension members.
+type Problem struct {
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Status    int       `json:"status"`
+	Detail    string    `json:"detail,omitempty"`
+	Instance  string    `json:"instance,omitempty"`
+	Code      ErrorCode `json:"code"`
+	RequestID string    `json:"requestId,omitempty"`
+}
+
+func NewProblem(status int, code ErrorCode, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+func (p *Problem) Error() string {
+	return string(p.Code) + ": " + p.Detail
+}
+
+// ProblemFor maps err to a Problem:
+//
+//	*Problem                              as is
+//	usecases.ErrInvalidParameter          400 invalid_parameter
+//	usecases.ErrNotFound                  404 not_found
+//	context.Canceled                      499 client_closed_request
+//	client.ErrCircuitOpen, ErrRateLimited 503 upstream_unavailable
+//	context.DeadlineExceeded              504 upstream_unavailable
+//	upstream status and transport errors  502 upstream_unavailable
+//	anything else                         500 internal_error
+//
+// Only the messages of invalid_parameter and not_found errors are exposed.
+// writeError reports a request cancelled by server shutdown as 503
+// shutting_down instead of 499.
+func ProblemFor(err error) *Problem {
+	var problem *Problem
+	var statusErr *client.StatusError
+	var transportErr *url.Error
+
+	switch {
+	case errors.As(err, &problem):
+		copied := *problem
+		return &copied
+	case errors.Is(err, usecases.ErrInvalidParameter):
+		return NewProblem(http.StatusBadRequest, CodeInvalidParameter, err.Error())
+	case errors.Is(err, usecases.ErrNotFound):
+		return NewProblem(http.StatusNotFound, CodeNotFound, err.Error())
+	case errors.Is(err, context.Canceled):
+		problem := NewProblem(StatusClientClosedRequest, CodeClientClosedRequest, "The client closed the request")
+		problem.Title = "Client Closed Request"
+		return problem
+	case errors.Is(err, client.ErrCircuitOpen), errors.Is(err, client.ErrRateLimited):
+		return NewProblem(http.StatusServiceUnavailable, CodeUpstreamUnavailable, "An upstream service is temporarily unavailable")
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewProblem(http.StatusGatewayTimeout, CodeUpstreamUnavailable, "An upstream service did not answer in time")
+	case errors.As(err, &statusErr), errors.As(err, &transportErr):
+		return NewProblem(http.StatusBadGateway, CodeUpstreamUnavailable, "An upstream service failed")
+	default:
+		return NewProblem(http.StatusInternalServerError, CodeInternal, "Internal server error")
+	}
+}
+
+// writeError renders err as application/problem+json, tagged with the
+// request path and ID.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := ProblemFor(err)
+	if problem.Code == CodeClientClosedRequest && shuttingDown(r.Context()) {
+		problem = NewProblem(http.StatusServiceUnavailable, CodeShuttingDown, "The server is shutting down")
+	}
+	problem.Instance = r.URL.Path
+	problem.RequestID = RequestIDFromContext(r.Context())
+
+	body, _ := json.Marshal(problem)
+
+	header := w.Header()
+	header.Del("Content-Language")
+	header.Set("Content-Type", problemContentType)
+	header.Set("X-Content-Type-Options", "nosniff")
+	if problem.Status == http.StatusServiceUnavailable {
+		header.Set("Retry-After", "30")
+	}
+	w.WriteHeader(problem.Status)
+	w.Write(body)
+}
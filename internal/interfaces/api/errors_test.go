@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+func TestProblemFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   ErrorCode
+	}{
+		{"invalid parameter", fmt.Errorf("%w: level", usecases.ErrInvalidParameter), http.StatusBadRequest, CodeInvalidParameter},
+		{"not found", fmt.Errorf("%w: champion", usecases.ErrNotFound), http.StatusNotFound, CodeNotFound},
+		{"client closed request", fmt.Errorf("failed to execute request: %w", context.Canceled), StatusClientClosedRequest, CodeClientClosedRequest},
+		{"circuit open", client.ErrCircuitOpen, http.StatusServiceUnavailable, CodeUpstreamUnavailable},
+		{"rate limited", client.ErrRateLimited, http.StatusServiceUnavailable, CodeUpstreamUnavailable},
+		{"deadline", context.DeadlineExceeded, http.StatusGatewayTimeout, CodeUpstreamUnavailable},
+		{"upstream status", &client.StatusError{StatusCode: http.StatusInternalServerError}, http.StatusBadGateway, CodeUpstreamUnavailable},
+		{"transport", &url.Error{Op: "Get", URL: "http://upstream", Err: errors.New("connection refused")}, http.StatusBadGateway, CodeUpstreamUnavailable},
+		{"other", errors.New("boom"), http.StatusInternalServerError, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := ProblemFor(tt.err)
+			if problem.Status != tt.wantStatus || problem.Code != tt.wantCode {
+				t.Errorf("got %d %s, want %d %s", problem.Status, problem.Code, tt.wantStatus, tt.wantCode)
+			}
+			if problem.Title == "" {
+				t.Error("problem has no title")
+			}
+		})
+	}
+}
+
+func TestWriteErrorCancelledRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		shutdown       bool
+		wantStatus     int
+		wantCode       ErrorCode
+		wantRetryAfter string
+	}{
+		{name: "client gone", wantStatus: StatusClientClosedRequest, wantCode: CodeClientClosedRequest},
+		{name: "server shutdown", shutdown: true, wantStatus: http.StatusServiceUnavailable, wantCode: CodeShuttingDown, wantRetryAfter: "30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(0, http.NotFoundHandler(), logger.NewJSONLogger(io.Discard, logger.LevelError))
+			defer server.cancel()
+
+			// Requests get a child of the base context, cancelled either by
+			// their connection closing or by Shutdown cancelling the base.
+			ctx, closeConn := context.WithCancel(server.server.BaseContext(nil))
+			defer closeConn()
+			if tt.shutdown {
+				server.cancel()
+			} else {
+				closeConn()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/champions/movement-speed", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+			writeError(rec, req, fmt.Errorf("failed to execute request: %w", ctx.Err()))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			var problem Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil || problem.Code != tt.wantCode || problem.Status != tt.wantStatus {
+				t.Errorf("problem %+v (%v), want %d %s", problem, err, tt.wantStatus, tt.wantCode)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+				t.Errorf("Retry-After %q, want %q", got, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestWriteErrorCancelledOutsideServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rec := httptest.NewRecorder()
+	writeError(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx), ctx.Err())
+
+	if rec.Code != StatusClientClosedRequest {
+		t.Errorf("status %d, want %d", rec.Code, StatusClientClosedRequest)
+	}
+}
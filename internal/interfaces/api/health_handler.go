@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+type HealthHandler struct {
+	breakers *client.CircuitBreakers
+	logger   logger.Logger
+}
+
+func NewHealthHandler(breakers *client.CircuitBreakers, logger logger.Logger) *HealthHandler {
+	return &HealthHandler{
+		breakers: breakers,
+		logger:   logger,
+	}
+}
+
+// GetHealth reports the server as up together with the circuit breaker state
+// of every upstream host. The status is "degraded" while any circuit is not
+// closed, which tells an unavailable upstream apart from a failing server.
+// It always answers 200 because the process itself is healthy.
+func (h *HealthHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
+	upstreams := h.breakers.Snapshots()
+
+	status := "ok"
+	for _, upstream := range upstreams {
+		if upstream.State != client.StateClosed {
+			status = "degraded"
+		}
+	}
+
+	response := struct {
+		Status    string                   `json:"status"`
+		Upstreams []client.BreakerSnapshot `json:"upstreams"`
+	}{
+		Status:    status,
+		Upstreams: upstreams,
+	}
+
+	jsonResponse, err := json.Marshal(response)
+	if err != nil {
+		h.logger.Error("Failed to marshal health response: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(jsonResponse)
+}
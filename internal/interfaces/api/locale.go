@@ -0,0 +1,62 @@
+package api
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// negotiateLocale picks the supported Data Dragon locale that best matches an
+// Accept-Language header, e.g. "pt-BR,pt;q=0.9,en;q=0.8". A language range
+// matches a locale exactly ("pt-BR" is "pt_BR") or by its primary language
+// ("pt" and "pt-PT" are the first supported "pt_*" locale). Ranges are tried
+// in order of quality, and the wildcard "*" is ignored. It returns "" when
+// nothing matches.
+func negotiateLocale(acceptLanguage string, supported []string) string {
+	type languageRange struct {
+		tag     string
+		quality float64
+	}
+
+	var ranges []languageRange
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.TrimSpace(fields[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		ranges = append(ranges, languageRange{tag: tag, quality: quality})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, r := range ranges {
+		tag := strings.Replace(r.tag, "-", "_", -1)
+		for _, locale := range supported {
+			if strings.EqualFold(locale, tag) {
+				return locale
+			}
+		}
+		language := strings.ToLower(strings.SplitN(tag, "_", 2)[0])
+		for _, locale := range supported {
+			if strings.HasPrefix(strings.ToLower(locale), language+"_") {
+				return locale
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+var testLocales = []string{"en_US", "en_GB", "pt_BR", "es_ES", "es_MX", "ko_KR"}
+
+// championRepository serves champions in whatever locale is asked for and
+// records the last one.
+type championRepository struct {
+	champions []entities.Champion
+	locale    string
+}
+
+func (r *championRepository) GetAllChampions(ctx context.Context, locale string) ([]entities.Champion, error) {
+	r.locale = locale
+	champions := make([]entities.Champion, len(r.champions))
+	for i, champion := range r.champions {
+		champion.Locale = locale
+		champions[i] = champion
+	}
+	return champions, nil
+}
+
+func (r *championRepository) GetLocales(ctx context.Context) ([]string, error) {
+	return testLocales, nil
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		want           string
+	}{
+		{name: "empty", acceptLanguage: "", want: ""},
+		{name: "exact", acceptLanguage: "pt-BR", want: "pt_BR"},
+		{name: "case", acceptLanguage: "PT-br", want: "pt_BR"},
+		{name: "underscore", acceptLanguage: "es_MX", want: "es_MX"},
+		{name: "language only", acceptLanguage: "es", want: "es_ES"},
+		{name: "unsupported region", acceptLanguage: "pt-PT", want: "pt_BR"},
+		{name: "region fallback before lower q", acceptLanguage: "en-AU, en-GB;q=0.9", want: "en_US"},
+		{name: "unsupported language", acceptLanguage: "fr-FR", want: ""},
+		{name: "first supported", acceptLanguage: "fr-FR, ko-KR", want: "ko_KR"},
+		{name: "q-values", acceptLanguage: "pt-BR;q=0.5, ko-KR;q=0.8, es-MX;q=0.7", want: "ko_KR"},
+		{name: "default q is 1", acceptLanguage: "es-MX;q=0.9, pt-BR", want: "pt_BR"},
+		{name: "equal q keeps order", acceptLanguage: "es-MX;q=0.5, pt-BR;q=0.5", want: "es_MX"},
+		{name: "q=0 excludes", acceptLanguage: "ko-KR;q=0, pt-BR;q=0.1", want: "pt_BR"},
+		{name: "only q=0", acceptLanguage: "ko-KR;q=0", want: ""},
+		{name: "wildcard", acceptLanguage: "*", want: ""},
+		{name: "wildcard first", acceptLanguage: "*, ko-KR;q=0.5", want: "ko_KR"},
+		{name: "whitespace", acceptLanguage: "  es-MX ;  q=0.4 ,ko-KR ; q=0.2 ", want: "es_MX"},
+		{name: "malformed q", acceptLanguage: "ko-KR;q=0.9, pt-BR;q=high", want: "pt_BR"},
+		{name: "empty ranges", acceptLanguage: ",;q=1,, ;, ko-KR", want: "ko_KR"},
+		{name: "garbage", acceptLanguage: ";;;===", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiateLocale(tt.acceptLanguage, testLocales); got != tt.want {
+				t.Errorf("negotiateLocale(%q) = %q, want %q", tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetChampionsByMovementSpeedLocale(t *testing.T) {
+	tests := []struct {
+		name            string
+		query           string
+		acceptLanguage  string
+		wantLocale      string
+		wantContentLang string
+	}{
+		{name: "default", wantLocale: "en_US", wantContentLang: "en-US"},
+		{name: "header", acceptLanguage: "pt-BR,pt;q=0.9", wantLocale: "pt_BR", wantContentLang: "pt-BR"},
+		{name: "no match", acceptLanguage: "fr-FR", wantLocale: "en_US", wantContentLang: "en-US"},
+		{name: "query overrides header", query: "?locale=ko_KR", acceptLanguage: "pt-BR", wantLocale: "ko_KR", wantContentLang: "ko-KR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &championRepository{champions: []entities.Champion{{ID: "Ahri", Name: "Ahri", MovementSpeed: 330}}}
+			handler := NewMovementSpeedHandler(usecases.NewChampionUseCase(repo), logger.NewJSONLogger(io.Discard, logger.LevelError))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/champions/movement-speed"+tt.query, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			rec := httptest.NewRecorder()
+			handler.GetChampionsByMovementSpeed(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			if repo.locale != tt.wantLocale {
+				t.Errorf("fetched locale %q, want %q", repo.locale, tt.wantLocale)
+			}
+			var body struct {
+				Locale string `json:"locale"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Locale != tt.wantLocale {
+				t.Errorf("response locale %q (%v), want %q", body.Locale, err, tt.wantLocale)
+			}
+			if got := rec.Header().Get("Content-Language"); got != tt.wantContentLang {
+				t.Errorf("Content-Language %q, want %q", got, tt.wantContentLang)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Language" {
+				t.Errorf("Vary %q, want Accept-Language", got)
+			}
+		})
+	}
+}
@@ -3,24 +3,38 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sync"
 
 	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
 )
 
+// ErrNotModified is returned by GetChampionsByMovementSpeed when the API
+// reports that the data has not changed since the previous successful call.
+var ErrNotModified = errors.New("champion data not modified")
+
 type MovementSpeedClient struct {
 	httpClient client.HTTPClient
 	baseURL    string
+	locale     string
 	logger     logger.Logger
+
+	mu   sync.Mutex
+	etag string
 }
 
-func NewMovementSpeedClient(httpClient client.HTTPClient, baseURL string, logger logger.Logger) *MovementSpeedClient {
+// NewMovementSpeedClient requests champion names and titles in locale, or in
+// the API's default locale when locale is empty.
+func NewMovementSpeedClient(httpClient client.HTTPClient, baseURL, locale string, logger logger.Logger) *MovementSpeedClient {
 	return &MovementSpeedClient{
 		httpClient: httpClient,
 		baseURL:    baseURL,
+		locale:     locale,
 		logger:     logger,
 	}
 }
@@ -28,14 +42,23 @@ func NewMovementSpeedClient(httpClient client.HTTPClient, baseURL string, logger
 func (c *MovementSpeedClient) GetChampionsByMovementSpeed(ctx context.Context) ([]entities.ChampionRecord, error) {
 	c.logger.Info("Fetching champions by movement speed from API")
 
-	url := fmt.Sprintf("%s/api/champions/movement-speed", c.baseURL)
-	
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	endpoint := fmt.Sprintf("%s/api/champions/movement-speed", c.baseURL)
+	if c.locale != "" {
+		endpoint += "?locale=" + url.QueryEscape(c.locale)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		c.logger.Error("Failed to create request: %v", err)
 		return nil, err
 	}
 
+	c.mu.Lock()
+	if c.etag != "" {
+		req.Header.Set("If-None-Match", c.etag)
+	}
+	c.mu.Unlock()
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		c.logger.Error("Failed to send request: %v", err)
@@ -43,19 +66,26 @@ func (c *MovementSpeedClient) GetChampionsByMovementSpeed(ctx context.Context) (
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		c.logger.Info("Champion data not modified since last fetch")
+		return nil, ErrNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		c.logger.Error("API returned non-OK status: %d", resp.StatusCode)
 		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 
 	var apiResponse struct {
-		Count     int `json:"count"`
+		Locale    string `json:"locale"`
+		Count     int    `json:"count"`
 		Champions []struct {
-			Rank          int     `json:"rank"`
-			ID            string  `json:"id"`
-			Name          string  `json:"name"`
-			Title         string  `json:"title"`
-			MovementSpeed float64 `json:"movementSpeed"`
+			Rank          int                    `json:"rank"`
+			ID            string                 `json:"id"`
+			Name          string                 `json:"name"`
+			Title         string                 `json:"title"`
+			MovementSpeed float64                `json:"movementSpeed"`
+			Stats         entities.ChampionStats `json:"stats"`
 		} `json:"champions"`
 	}
 
@@ -64,17 +94,35 @@ func (c *MovementSpeedClient) GetChampionsByMovementSpeed(ctx context.Context) (
 		return nil, err
 	}
 
+	if apiResponse.Locale == "" {
+		apiResponse.Locale = entities.DefaultLocale
+	}
+
 	champions := make([]entities.ChampionRecord, 0, len(apiResponse.Champions))
 	for _, c := range apiResponse.Champions {
 		champions = append(champions, entities.ChampionRecord{
 			ChampionID:    c.ID,
 			Name:          c.Name,
 			Title:         c.Title,
+			Locale:        apiResponse.Locale,
 			MovementSpeed: c.MovementSpeed,
+			Stats:         c.Stats,
 			Rank:          c.Rank,
 		})
 	}
 
+	c.mu.Lock()
+	c.etag = resp.Header.Get("ETag")
+	c.mu.Unlock()
+
 	c.logger.Success("Successfully fetched %d champions from API", len(champions))
 	return champions, nil
 }
+
+// ResetETag forgets the ETag of the last response so that the next call
+// downloads the full payload, e.g. after the previous one failed to persist.
+func (c *MovementSpeedClient) ResetETag() {
+	c.mu.Lock()
+	c.etag = ""
+	c.mu.Unlock()
+}
@@ -4,74 +4,150 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"sort"
+	"strings"
 
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
 	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
 )
 
+// MovementSpeedPath is the path GetChampionsByMovementSpeed is mounted on.
+const MovementSpeedPath = "/api/champions/movement-speed"
+
 type MovementSpeedHandler struct {
 	championUseCase usecases.ChampionUseCase
 	logger          logger.Logger
+	validators      *responseValidators
 }
 
 func NewMovementSpeedHandler(championUseCase usecases.ChampionUseCase, logger logger.Logger) *MovementSpeedHandler {
 	return &MovementSpeedHandler{
 		championUseCase: championUseCase,
 		logger:          logger,
+		validators:      newResponseValidators(),
 	}
 }
 
+// GetChampionsByMovementSpeed serves /api/champions/movement-speed. It
+// accepts the following optional query parameters:
+//
+//	locale    Data Dragon locale, negotiated from Accept-Language when absent
+//	minSpeed  minimum movement speed
+//	maxSpeed  maximum movement speed
+//	name      name or ID prefix, ignoring case
+//	tags      comma separated roles a champion must all have, e.g. Assassin,Mage
+//	limit     page size, every matching champion when absent
+//	offset    number of matching champions to skip
+//	fields    comma separated champion fields to return, e.g. id,name,rank
+//
+// Unknown parameters are rejected. Ranks are positions in the full ranking,
+// before filtering.
 func (h *MovementSpeedHandler) GetChampionsByMovementSpeed(w http.ResponseWriter, r *http.Request) {
 	h.logger.Info("API request received: Get champions by movement speed")
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
 
-	ctx := context.Background()
-	champions, err := h.championUseCase.GetAllChampions(ctx)
+	query := r.URL.Query()
+	params, err := parseListParams(query)
 	if err != nil {
-		h.logger.Error("Failed to get champions: %v", err)
-		http.Error(w, "Failed to retrieve champion data", http.StatusInternalServerError)
+		h.logger.Error("Invalid champion list request: %v", err)
+		writeError(w, r, err)
 		return
 	}
 
-	sort.Slice(champions, func(i, j int) bool {
-		return champions[i].MovementSpeed > champions[j].MovementSpeed
+	ctx := r.Context()
+	locale := query.Get("locale")
+	if locale == "" {
+		locale = h.negotiateLocale(ctx, r.Header.Get("Accept-Language"))
+	}
+
+	champions, err := h.championUseCase.RankChampions(ctx, usecases.RankOptions{
+		Stat:    entities.StatMoveSpeed,
+		Order:   entities.SortDescending,
+		Ranking: entities.RankingCompetition,
+		Locale:  locale,
 	})
+	if err != nil {
+		h.logger.Error("Failed to get champions: %v", err)
+		writeError(w, r, err)
+		return
+	}
 
-	type ChampionResponse struct {
-		Rank         int     `json:"rank"`
-		ID           string  `json:"id"`
-		Name         string  `json:"name"`
-		Title        string  `json:"title"`
-		MovementSpeed float64 `json:"movementSpeed"`
+	version := ""
+	if len(champions) > 0 {
+		version = champions[0].Champion.Version
+		locale = champions[0].Champion.Locale
 	}
+	if locale == "" {
+		locale = entities.DefaultLocale
+	}
+	w.Header().Set("Content-Language", strings.Replace(locale, "_", "-", -1))
+
+	filtered := params.filter.FilterRanked(champions)
+	page := paginate(filtered, params.offset, params.limit)
 
 	response := struct {
-		Count     int                `json:"count"`
-		Champions []ChampionResponse `json:"champions"`
+		Version    string      `json:"version"`
+		Locale     string      `json:"locale"`
+		Count      int         `json:"count"`
+		Total      int         `json:"total"`
+		Offset     int         `json:"offset"`
+		Limit      int         `json:"limit,omitempty"`
+		NextOffset *int        `json:"nextOffset,omitempty"`
+		Champions  interface{} `json:"champions"`
 	}{
-		Count:     len(champions),
-		Champions: make([]ChampionResponse, 0, len(champions)),
+		Version: version,
+		Locale:  locale,
+		Count:   len(page),
+		Total:   len(filtered),
+		Offset:  params.offset,
+		Limit:   params.limit,
+	}
+	if next := params.offset + len(page); next < len(filtered) {
+		response.NextOffset = &next
 	}
 
-	for i, champion := range champions {
-		response.Champions = append(response.Champions, ChampionResponse{
-			Rank:         i + 1,
-			ID:           champion.ID,
-			Name:         champion.Name,
-			Title:        champion.Title,
-			MovementSpeed: champion.MovementSpeed,
-		})
+	if len(params.fields) == 0 {
+		items := make([]championResponse, 0, len(page))
+		for _, ranked := range page {
+			items = append(items, newChampionResponse(ranked))
+		}
+		response.Champions = items
+	} else {
+		items := make([]map[string]interface{}, 0, len(page))
+		for _, ranked := range page {
+			items = append(items, newChampionResponse(ranked).project(params.fields))
+		}
+		response.Champions = items
 	}
 
 	jsonResponse, err := json.Marshal(response)
 	if err != nil {
 		h.logger.Error("Failed to marshal response: %v", err)
-		http.Error(w, "Failed to generate response", http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	h.logger.Success("Successfully returned %d champions sorted by movement speed", len(champions))
-	w.Write(jsonResponse)
+	h.logger.Success("Successfully returned %d of %d champions sorted by movement speed", len(page), len(filtered))
+	h.validators.writeConditional(w, r, jsonResponse, version)
+}
+
+// negotiateLocale falls back to the default locale when there is no
+// Accept-Language match or the supported locales cannot be listed.
+func (h *MovementSpeedHandler) negotiateLocale(ctx context.Context, acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return entities.DefaultLocale
+	}
+
+	supported, err := h.championUseCase.GetLocales(ctx)
+	if err != nil {
+		h.logger.Warn("Failed to list locales, using %s: %v", entities.DefaultLocale, err)
+		return entities.DefaultLocale
+	}
+
+	if locale := negotiateLocale(acceptLanguage, supported); locale != "" {
+		return locale
+	}
+	return entities.DefaultLocale
 }
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+// PlayerMasteryPath is the path GetTopMasteryChampions is mounted on.
+const PlayerMasteryPath = "/api/players/mastery"
+
+type PlayerMasteryHandler struct {
+	playerUseCase usecases.PlayerUseCase
+	logger        logger.Logger
+}
+
+func NewPlayerMasteryHandler(playerUseCase usecases.PlayerUseCase, logger logger.Logger) *PlayerMasteryHandler {
+	return &PlayerMasteryHandler{
+		playerUseCase: playerUseCase,
+		logger:        logger,
+	}
+}
+
+// GetTopMasteryChampions serves /api/players/mastery?riotId=Name%23TAG&region=euw1&count=5.
+// region is a platform routing value and defaults to na1.
+func (h *PlayerMasteryHandler) GetTopMasteryChampions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("API request received: Get top mastery champions")
+
+	w.Header().Set("Content-Type", "application/json")
+
+	query := r.URL.Query()
+	riotID := query.Get("riotId")
+	if riotID == "" {
+		writeError(w, r, fmt.Errorf("%w: missing required parameter riotId", usecases.ErrInvalidParameter))
+		return
+	}
+
+	count := 0
+	if value := query.Get("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("%w: count must be an integer", usecases.ErrInvalidParameter))
+			return
+		}
+		count = parsed
+	}
+
+	mastery, err := h.playerUseCase.GetTopMasteryChampions(r.Context(), entities.Platform(query.Get("region")), riotID, count)
+	if err != nil {
+		h.logger.Error("Failed to get top mastery champions: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	type MasteryChampionResponse struct {
+		ChampionKey       int64                   `json:"championKey"`
+		ID                string                  `json:"id,omitempty"`
+		Name              string                  `json:"name,omitempty"`
+		Title             string                  `json:"title,omitempty"`
+		MasteryLevel      int                     `json:"masteryLevel"`
+		MasteryPoints     int64                   `json:"masteryPoints"`
+		LastPlayTime      int64                   `json:"lastPlayTime"`
+		MovementSpeed     float64                 `json:"movementSpeed,omitempty"`
+		MovementSpeedRank int                     `json:"movementSpeedRank,omitempty"`
+		Stats             *entities.ChampionStats `json:"stats,omitempty"`
+	}
+
+	response := struct {
+		Region    string                    `json:"region"`
+		PUUID     string                    `json:"puuid"`
+		GameName  string                    `json:"gameName"`
+		TagLine   string                    `json:"tagLine"`
+		Count     int                       `json:"count"`
+		Champions []MasteryChampionResponse `json:"champions"`
+	}{
+		Region:    string(mastery.Platform),
+		PUUID:     mastery.Account.PUUID,
+		GameName:  mastery.Account.GameName,
+		TagLine:   mastery.Account.TagLine,
+		Count:     len(mastery.Champions),
+		Champions: make([]MasteryChampionResponse, 0, len(mastery.Champions)),
+	}
+
+	for _, entry := range mastery.Champions {
+		champion := MasteryChampionResponse{
+			ChampionKey:   entry.Mastery.ChampionID,
+			MasteryLevel:  entry.Mastery.ChampionLevel,
+			MasteryPoints: entry.Mastery.ChampionPoints,
+			LastPlayTime:  entry.Mastery.LastPlayTime,
+		}
+		if entry.Champion != nil {
+			champion.ID = entry.Champion.ID
+			champion.Name = entry.Champion.Name
+			champion.Title = entry.Champion.Title
+			champion.MovementSpeed = entry.Champion.MovementSpeed
+			champion.MovementSpeedRank = entry.MovementSpeedRank
+			champion.Stats = &entry.Champion.Stats
+		}
+		response.Champions = append(response.Champions, champion)
+	}
+
+	jsonResponse, err := json.Marshal(response)
+	if err != nil {
+		h.logger.Error("Failed to marshal response: %v", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.logger.Success("Successfully returned %d mastery champions for %s", len(mastery.Champions), riotID)
+	w.Write(jsonResponse)
+}
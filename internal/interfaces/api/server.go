@@ -3,27 +3,67 @@ package api
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
 )
 
+// DefaultWriteTimeout bounds writing a response, measured from the end of
+// the request headers.
+const DefaultWriteTimeout = 15 * time.Second
+
+// writeTimeoutMargin leaves a request that timed out enough time to write
+// its error response.
+const writeTimeoutMargin = 5 * time.Second
+
+// serverContextKey carries the base context of the server's requests, which
+// is only cancelled by Shutdown.
+type serverContextKey struct{}
+
 type Server struct {
 	server *http.Server
 	logger logger.Logger
+	// cancel cancels the base context of every request.
+	cancel context.CancelFunc
 }
 
-func NewServer(port int, handler http.Handler, logger logger.Logger) *Server {
-	return &Server{
-		server: &http.Server{
-			Addr:         fmt.Sprintf(":%d", port),
-			Handler:      handler,
-			ReadTimeout:  15 * time.Second,
-			WriteTimeout: 15 * time.Second,
-			IdleTimeout:  60 * time.Second,
+// ServerOption customizes a Server created by NewServer.
+type ServerOption func(*http.Server)
+
+// WithRequestTimeout raises the write timeout above timeout, the longest a
+// request may run, when DefaultWriteTimeout would cut its response off.
+func WithRequestTimeout(timeout time.Duration) ServerOption {
+	return func(s *http.Server) {
+		if writeTimeout := timeout + writeTimeoutMargin; writeTimeout > s.WriteTimeout {
+			s.WriteTimeout = writeTimeout
+		}
+	}
+}
+
+func NewServer(port int, handler http.Handler, logger logger.Logger, options ...ServerOption) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = context.WithValue(ctx, serverContextKey{}, ctx)
+
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", port),
+		Handler:      handler,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: DefaultWriteTimeout,
+		IdleTimeout:  60 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
 		},
+	}
+	for _, option := range options {
+		option(server)
+	}
+
+	return &Server{
+		server: server,
 		logger: logger,
+		cancel: cancel,
 	}
 }
 
@@ -32,7 +72,18 @@ func (s *Server) Start() error {
 	return s.server.ListenAndServe()
 }
 
+// Shutdown stops accepting connections and waits for in-flight requests
+// until ctx is done. Requests still running then are cancelled, which aborts
+// their upstream calls.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("Shutting down API server")
+	defer s.cancel()
 	return s.server.Shutdown(ctx)
 }
+
+// shuttingDown reports whether ctx belongs to a request that was cancelled
+// because its server shut down, rather than because its client went away.
+func shuttingDown(ctx context.Context) bool {
+	base, ok := ctx.Value(serverContextKey{}).(context.Context)
+	return ok && base.Err() != nil
+}
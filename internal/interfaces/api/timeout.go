@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultRequestTimeout bounds a request, including every upstream call it
+// makes, unless its route is configured otherwise.
+const DefaultRequestTimeout = 10 * time.Second
+
+// WithTimeout cancels the context of requests to next after timeout. Handlers
+// pass the request context down to the repositories, so the upstream calls of
+// a request stop when it times out or the client disconnects.
+func WithTimeout(timeout time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RoutePatterns are the patterns the API routes are registered with, the only
+// routes ParseRouteTimeouts accepts.
+var RoutePatterns = []string{MovementSpeedPath, ChampionRankPath, ChampionPathPrefix, PlayerMasteryPath}
+
+// RouteTimeouts holds per-route request timeouts, keyed by the pattern the
+// route is registered with.
+type RouteTimeouts struct {
+	Default time.Duration
+	Routes  map[string]time.Duration
+}
+
+// ParseRouteTimeouts parses a comma separated list of pattern=duration pairs,
+// e.g. "/api/champions/movement-speed=20s,/api/players/mastery=5s". Patterns
+// must be listed in RoutePatterns, so that a misspelled route is reported
+// rather than silently left at the default timeout.
+func ParseRouteTimeouts(value string, defaultTimeout time.Duration) (RouteTimeouts, error) {
+	timeouts := RouteTimeouts{Default: defaultTimeout, Routes: make(map[string]time.Duration)}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.SplitN(part, "=", 2)
+		if len(fields) != 2 || fields[0] == "" {
+			return timeouts, fmt.Errorf("invalid route timeout %q, expected pattern=duration", part)
+		}
+		if !knownRoute(fields[0]) {
+			return timeouts, fmt.Errorf("unknown route %q, expected one of %s", fields[0], strings.Join(RoutePatterns, ", "))
+		}
+		timeout, err := time.ParseDuration(fields[1])
+		if err != nil || timeout <= 0 {
+			return timeouts, fmt.Errorf("invalid timeout %q for route %s", fields[1], fields[0])
+		}
+		timeouts.Routes[fields[0]] = timeout
+	}
+	return timeouts, nil
+}
+
+func knownRoute(pattern string) bool {
+	for _, candidate := range RoutePatterns {
+		if pattern == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// For returns the timeout of the route registered with pattern.
+func (t RouteTimeouts) For(pattern string) time.Duration {
+	if timeout, ok := t.Routes[pattern]; ok {
+		return timeout
+	}
+	if t.Default > 0 {
+		return t.Default
+	}
+	return DefaultRequestTimeout
+}
+
+// Max returns the longest timeout of any route.
+func (t RouteTimeouts) Max() time.Duration {
+	longest := t.For("")
+	for _, timeout := range t.Routes {
+		if timeout > longest {
+			longest = timeout
+		}
+	}
+	return longest
+}
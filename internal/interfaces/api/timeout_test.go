@@ -0,0 +1,95 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+func TestParseRouteTimeouts(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    map[string]time.Duration
+		wantErr bool
+	}{
+		{value: "", want: map[string]time.Duration{}},
+		{value: " , ", want: map[string]time.Duration{}},
+		{value: "/api/champions/movement-speed=20s", want: map[string]time.Duration{"/api/champions/movement-speed": 20 * time.Second}},
+		{value: "/api/champions/=2s, /api/players/mastery=1m", want: map[string]time.Duration{"/api/champions/": 2 * time.Second, "/api/players/mastery": time.Minute}},
+		{value: "/api/champions/rank=5s,/api/champions/rank=7s", want: map[string]time.Duration{"/api/champions/rank": 7 * time.Second}},
+		{value: "/api/champions/rank", wantErr: true},
+		{value: "=5s", wantErr: true},
+		{value: "/api/champions/rank=", wantErr: true},
+		{value: "/api/champions/rank=30", wantErr: true},
+		{value: "/api/champions/rank=0s", wantErr: true},
+		{value: "/api/champions/rank=-5s", wantErr: true},
+		{value: "/api/champions/rank/=30s", wantErr: true},
+		{value: "/api/champion/rank=30s", wantErr: true},
+		{value: "/api/champions=30s", wantErr: true},
+		{value: "/api/champions/Kassadin=30s", wantErr: true},
+		{value: "api/players/mastery=5s", wantErr: true},
+		{value: "/API/players/mastery=5s", wantErr: true},
+		{value: "/health=1s", wantErr: true},
+		{value: "/api/players/mastery=5s,/api/player/mastery=5s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			timeouts, err := ParseRouteTimeouts(tt.value, DefaultRequestTimeout)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got %v, want an error", timeouts.Routes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(timeouts.Routes, tt.want) {
+				t.Errorf("got %v, want %v", timeouts.Routes, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouteTimeoutsFor(t *testing.T) {
+	timeouts, err := ParseRouteTimeouts("/api/players/mastery=5s", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := timeouts.For(PlayerMasteryPath); got != 5*time.Second {
+		t.Errorf("configured route: %s, want 5s", got)
+	}
+	if got := timeouts.For(ChampionRankPath); got != DefaultRequestTimeout {
+		t.Errorf("other route: %s, want %s", got, DefaultRequestTimeout)
+	}
+}
+
+func TestServerWriteTimeoutCoversRouteTimeouts(t *testing.T) {
+	tests := []struct {
+		routeTimeouts string
+		want          time.Duration
+	}{
+		{routeTimeouts: "", want: DefaultWriteTimeout},
+		{routeTimeouts: "/api/players/mastery=5s", want: DefaultWriteTimeout},
+		{routeTimeouts: "/api/champions/rank=30s,/api/players/mastery=5s", want: 35 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.routeTimeouts, func(t *testing.T) {
+			timeouts, err := ParseRouteTimeouts(tt.routeTimeouts, DefaultRequestTimeout)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			server := NewServer(8080, http.NotFoundHandler(), logger.NewJSONLogger(io.Discard, logger.LevelError), WithRequestTimeout(timeouts.Max()))
+			if got := server.server.WriteTimeout; got != tt.want {
+				t.Errorf("write timeout %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/tracing"
+)
+
+const (
+	DefaultTTL            = 10 * time.Minute
+	DefaultRefreshTimeout = 2 * time.Minute
+	DefaultRetryBackoff   = 30 * time.Second
+)
+
+type ChampionRepositoryConfig struct {
+	// TTL is how long a snapshot is served without going upstream.
+	TTL time.Duration
+	// RefreshAhead starts a background refresh once a snapshot is this
+	// close to expiring. Defaults to a fifth of TTL.
+	RefreshAhead time.Duration
+	// RefreshTimeout bounds a single upstream refresh.
+	RefreshTimeout time.Duration
+	// RetryBackoff is how long a snapshot whose refresh failed is served
+	// before the next refresh starts. Defaults to DefaultRetryBackoff.
+	RetryBackoff time.Duration
+}
+
+// ChampionRepository decorates a usecases.ChampionRepository with one
+// in-memory snapshot per locale. Only one upstream refresh per locale runs at
+// a time. Once a locale has a snapshot it is always served right away: close
+// to or past expiry it is refreshed in the background, and a failed or
+// hanging refresh keeps serving the last snapshot, which is not refreshed
+// again before the retry backoff has passed. Only callers of a locale
+// without a snapshot wait for the refresh, which is cancelled once every one
+// of them has given up. A background refresh always runs to completion.
+type ChampionRepository struct {
+	next           usecases.ChampionRepository
+	logger         logger.Logger
+	ttl            time.Duration
+	refreshAhead   time.Duration
+	refreshTimeout time.Duration
+	retryBackoff   time.Duration
+
+	mu        sync.Mutex
+	snapshots map[string]*snapshot
+}
+
+type snapshot struct {
+	champions []entities.Champion
+	fetchedAt time.Time
+	// lastFailure is when the last refresh of champions failed, or zero if
+	// it succeeded.
+	lastFailure time.Time
+	refresh     *refreshCall
+}
+
+type refreshCall struct {
+	done      chan struct{}
+	champions []entities.Champion
+	err       error
+
+	// cancel aborts the upstream fetch. It is nil for background refreshes.
+	cancel  context.CancelFunc
+	waiters int
+}
+
+func NewChampionRepository(next usecases.ChampionRepository, logger logger.Logger, config ChampionRepositoryConfig) *ChampionRepository {
+	ttl := config.TTL
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	refreshAhead := config.RefreshAhead
+	if refreshAhead <= 0 || refreshAhead >= ttl {
+		refreshAhead = ttl / 5
+	}
+	refreshTimeout := config.RefreshTimeout
+	if refreshTimeout <= 0 {
+		refreshTimeout = DefaultRefreshTimeout
+	}
+	retryBackoff := config.RetryBackoff
+	if retryBackoff <= 0 {
+		retryBackoff = DefaultRetryBackoff
+	}
+
+	return &ChampionRepository{
+		next:           next,
+		logger:         logger,
+		ttl:            ttl,
+		refreshAhead:   refreshAhead,
+		refreshTimeout: refreshTimeout,
+		retryBackoff:   retryBackoff,
+		snapshots:      make(map[string]*snapshot),
+	}
+}
+
+func (r *ChampionRepository) GetAllChampions(ctx context.Context, locale string) ([]entities.Champion, error) {
+	r.mu.Lock()
+	current, ok := r.snapshots[locale]
+	if !ok {
+		current = &snapshot{}
+		r.snapshots[locale] = current
+	}
+
+	if current.champions != nil {
+		age := time.Since(current.fetchedAt)
+		backingOff := time.Since(current.lastFailure) < r.retryBackoff
+		if age >= r.ttl-r.refreshAhead && current.refresh == nil && !backingOff {
+			if age < r.ttl {
+				r.logger.Info("Champion cache for %s expires in %s, refreshing in background", locale, (r.ttl - age).Round(time.Second))
+			} else {
+				r.logger.Info("Champion cache for %s expired %s ago, serving stale data while refreshing in background", locale, (age - r.ttl).Round(time.Second))
+			}
+			r.startRefresh(ctx, locale, current, false)
+		}
+		champions := copyChampions(current.champions)
+		r.mu.Unlock()
+		return champions, nil
+	}
+
+	call := current.refresh
+	if call == nil {
+		call = r.startRefresh(ctx, locale, current, true)
+	}
+	call.waiters++
+	r.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		r.leave(locale, current, call)
+		return nil, ctx.Err()
+	}
+
+	if call.err != nil {
+		return nil, call.err
+	}
+
+	return copyChampions(call.champions), nil
+}
+
+// Warm reports whether a snapshot of locale has been fetched, even if it has
+// since expired, so that GetAllChampions can answer without waiting for
+// upstream.
+func (r *ChampionRepository) Warm(locale string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.snapshots[locale]
+	return ok && current.champions != nil
+}
+
+// GetLocales is not cached here; the wrapped repository keeps the list.
+func (r *ChampionRepository) GetLocales(ctx context.Context) ([]string, error) {
+	return r.next.GetLocales(ctx)
+}
+
+// leave unregisters a waiter of call that gave up, and cancels the refresh
+// when it was the last one.
+func (r *ChampionRepository) leave(locale string, current *snapshot, call *refreshCall) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 || call.cancel == nil {
+		return
+	}
+
+	select {
+	case <-call.done:
+		return
+	default:
+	}
+
+	r.logger.Info("Every caller waiting for the champion cache for %s left, cancelling refresh", locale)
+	call.cancel()
+	if current.refresh == call {
+		current.refresh = nil
+	}
+}
+
+// startRefresh fetches a new snapshot of locale from the wrapped repository.
+// A cancellable refresh can be aborted by leave. The refresh is detached from
+// the cancellation of ctx but traced as part of the trace of the caller that
+// started it. The caller must hold r.mu.
+func (r *ChampionRepository) startRefresh(parent context.Context, locale string, current *snapshot, cancellable bool) *refreshCall {
+	ctx, cancel := context.WithTimeout(context.Background(), r.refreshTimeout)
+	ctx = tracing.ContextWithSpan(ctx, tracing.SpanFromContext(parent))
+	call := &refreshCall{done: make(chan struct{})}
+	if cancellable {
+		call.cancel = cancel
+	}
+	current.refresh = call
+
+	go func() {
+		defer cancel()
+
+		ctx, span := tracing.Start(ctx, "ChampionCache.refresh",
+			tracing.WithAttributes(tracing.String("locale", locale), tracing.Bool("background", !cancellable)))
+		champions, err := r.next.GetAllChampions(ctx, locale)
+		span.RecordError(err)
+		span.End()
+
+		r.mu.Lock()
+		call.champions, call.err = champions, err
+		switch {
+		case err == nil:
+			current.champions = champions
+			current.fetchedAt = time.Now()
+			current.lastFailure = time.Time{}
+			r.logger.Success("Champion cache for %s refreshed with %d champions", locale, len(champions))
+		case errors.Is(err, context.Canceled):
+			r.logger.Info("Champion cache refresh for %s cancelled", locale)
+		default:
+			current.lastFailure = time.Now()
+			if current.champions != nil {
+				r.logger.Error("Failed to refresh champion cache for %s, retrying in %s: %v", locale, r.retryBackoff, err)
+			} else {
+				r.logger.Error("Failed to refresh champion cache for %s: %v", locale, err)
+			}
+		}
+		if current.refresh == call {
+			current.refresh = nil
+		}
+		if current.champions == nil && current.refresh == nil && r.snapshots[locale] == current {
+			// Never cache unsupported locales, whose refresh always fails.
+			delete(r.snapshots, locale)
+		}
+		r.mu.Unlock()
+
+		close(call.done)
+	}()
+
+	return call
+}
+
+func copyChampions(champions []entities.Champion) []entities.Champion {
+	if champions == nil {
+		return nil
+	}
+	return append([]entities.Champion(nil), champions...)
+}
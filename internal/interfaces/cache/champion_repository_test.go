@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+// upstream serves champions until hang or fail is set. Once hang is set
+// every fetch blocks until its context is done, and once fail is set every
+// fetch fails.
+type upstream struct {
+	calls int32
+	hang  int32
+	fail  int32
+}
+
+var errUpstream = errors.New("upstream unavailable")
+
+func (u *upstream) GetAllChampions(ctx context.Context, locale string) ([]entities.Champion, error) {
+	atomic.AddInt32(&u.calls, 1)
+	if atomic.LoadInt32(&u.hang) == 1 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if atomic.LoadInt32(&u.fail) == 1 {
+		return nil, errUpstream
+	}
+	return []entities.Champion{{ID: "Ahri", Locale: locale}}, nil
+}
+
+func (u *upstream) GetLocales(ctx context.Context) ([]string, error) {
+	return []string{entities.DefaultLocale}, nil
+}
+
+func newTestRepository(next *upstream, ttl time.Duration) *ChampionRepository {
+	return NewChampionRepository(next, logger.NewJSONLogger(io.Discard, logger.LevelError), ChampionRepositoryConfig{
+		TTL:            ttl,
+		RefreshTimeout: time.Minute,
+	})
+}
+
+func TestGetAllChampionsServesExpiredSnapshotWhileUpstreamHangs(t *testing.T) {
+	next := &upstream{}
+	repo := newTestRepository(next, 20*time.Millisecond)
+
+	if _, err := repo.GetAllChampions(context.Background(), entities.DefaultLocale); err != nil {
+		t.Fatalf("initial fetch: %v", err)
+	}
+	atomic.StoreInt32(&next.hang, 1)
+	time.Sleep(30 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		champions, err := repo.GetAllChampions(ctx, entities.DefaultLocale)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if len(champions) != 1 || champions[0].ID != "Ahri" {
+			t.Fatalf("request %d returned %+v, want the stale snapshot", i, champions)
+		}
+	}
+
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&next.calls) < 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if calls := atomic.LoadInt32(&next.calls); calls != 2 {
+		t.Errorf("upstream called %d times, want 2: the initial fetch and one background refresh", calls)
+	}
+}
+
+func TestGetAllChampionsWithoutSnapshotWaitsForUpstream(t *testing.T) {
+	next := &upstream{hang: 1}
+	repo := newTestRepository(next, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := repo.GetAllChampions(ctx, entities.DefaultLocale); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if repo.Warm(entities.DefaultLocale) {
+		t.Error("cache is warm without a successful fetch")
+	}
+}
+
+func TestGetAllChampionsBacksOffAfterFailedRefresh(t *testing.T) {
+	next := &upstream{}
+	repo := NewChampionRepository(next, logger.NewJSONLogger(io.Discard, logger.LevelError), ChampionRepositoryConfig{
+		TTL:          10 * time.Millisecond,
+		RetryBackoff: 100 * time.Millisecond,
+	})
+
+	if _, err := repo.GetAllChampions(context.Background(), entities.DefaultLocale); err != nil {
+		t.Fatalf("initial fetch: %v", err)
+	}
+	atomic.StoreInt32(&next.fail, 1)
+	time.Sleep(20 * time.Millisecond)
+
+	// Upstream keeps failing. The first request after expiry starts a
+	// refresh and the following ones must not start another until the
+	// backoff has passed.
+	for deadline := time.Now().Add(60 * time.Millisecond); time.Now().Before(deadline); {
+		champions, err := repo.GetAllChampions(context.Background(), entities.DefaultLocale)
+		if err != nil || len(champions) != 1 {
+			t.Fatalf("got %+v, %v, want the stale snapshot", champions, err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if calls := atomic.LoadInt32(&next.calls); calls != 2 {
+		t.Fatalf("upstream called %d times within the backoff, want 2: the initial fetch and one refresh", calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	repo.GetAllChampions(context.Background(), entities.DefaultLocale)
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&next.calls) < 3 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if calls := atomic.LoadInt32(&next.calls); calls != 3 {
+		t.Errorf("upstream called %d times after the backoff, want 3", calls)
+	}
+}
@@ -8,8 +8,37 @@ import (
 	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
 	"github.com/marcopaulosilva/poc_devin/internal/domain/repositories"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/tracing"
 )
 
+const championColumns = `id, champion_id, name, title, locale, movement_speed, rank, created_at, updated_at,
+		hp, hp_per_level, mp, mp_per_level, armor, armor_per_level,
+		spell_block, spell_block_per_level, attack_range,
+		attack_damage, attack_damage_per_level, attack_speed, attack_speed_per_level`
+
+// trackedStats are the champion values whose changes are appended to
+// champion_stat_history, named after their champions table column.
+var trackedStats = []struct {
+	name  string
+	value func(*entities.ChampionRecord) float64
+}{
+	{"movement_speed", func(c *entities.ChampionRecord) float64 { return c.MovementSpeed }},
+	{"rank", func(c *entities.ChampionRecord) float64 { return float64(c.Rank) }},
+	{"hp", func(c *entities.ChampionRecord) float64 { return c.Stats.HP }},
+	{"hp_per_level", func(c *entities.ChampionRecord) float64 { return c.Stats.HPPerLevel }},
+	{"mp", func(c *entities.ChampionRecord) float64 { return c.Stats.MP }},
+	{"mp_per_level", func(c *entities.ChampionRecord) float64 { return c.Stats.MPPerLevel }},
+	{"armor", func(c *entities.ChampionRecord) float64 { return c.Stats.Armor }},
+	{"armor_per_level", func(c *entities.ChampionRecord) float64 { return c.Stats.ArmorPerLevel }},
+	{"spell_block", func(c *entities.ChampionRecord) float64 { return c.Stats.SpellBlock }},
+	{"spell_block_per_level", func(c *entities.ChampionRecord) float64 { return c.Stats.SpellBlockPerLevel }},
+	{"attack_range", func(c *entities.ChampionRecord) float64 { return c.Stats.AttackRange }},
+	{"attack_damage", func(c *entities.ChampionRecord) float64 { return c.Stats.AttackDamage }},
+	{"attack_damage_per_level", func(c *entities.ChampionRecord) float64 { return c.Stats.AttackDamagePerLevel }},
+	{"attack_speed", func(c *entities.ChampionRecord) float64 { return c.Stats.AttackSpeed }},
+	{"attack_speed_per_level", func(c *entities.ChampionRecord) float64 { return c.Stats.AttackSpeedPerLevel }},
+}
+
 type PostgresChampionRepository struct {
 	db     *sql.DB
 	logger logger.Logger
@@ -22,20 +51,51 @@ func NewPostgresChampionRepository(db *sql.DB, logger logger.Logger) repositorie
 	}
 }
 
-func (r *PostgresChampionRepository) SaveChampions(ctx context.Context, champions []entities.ChampionRecord) error {
+// SaveChampions upserts every champion and, in the same transaction, appends
+// a champion_stat_history row for each tracked value that differs from the
+// stored one. A champion that is not stored yet gets no history: its values
+// are in the champions table, and recording them would only repeat every
+// stat of every champion on the first sync. When ctx is traced, the transaction and each of its
+// statements get their own span.
+func (r *PostgresChampionRepository) SaveChampions(ctx context.Context, champions []entities.ChampionRecord) (err error) {
 	r.logger.Info("Saving %d champions to database", len(champions))
 
+	ctx, span := tracing.Start(ctx, "PostgresChampionRepository.SaveChampions",
+		tracing.WithAttributes(tracing.Int("champions", len(champions))))
+	defer func() {
+		span.RecordError(err)
+		span.End()
+	}()
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		r.logger.Error("Failed to begin transaction: %v", err)
 		return err
 	}
 
+	lockCtx, lockSpan := startStatementSpan(ctx, "SELECT", "champions")
+	current, err := r.lockCurrentChampions(lockCtx, tx)
+	lockSpan.RecordError(err)
+	lockSpan.End()
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to load current champions: %v", err)
+		return err
+	}
+
 	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO champions (champion_id, name, title, movement_speed, rank, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO champions (
+			champion_id, name, title, locale, movement_speed, rank, created_at, updated_at,
+			hp, hp_per_level, mp, mp_per_level, armor, armor_per_level,
+			spell_block, spell_block_per_level, attack_range,
+			attack_damage, attack_damage_per_level, attack_speed, attack_speed_per_level
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 		ON CONFLICT (champion_id) 
-		DO UPDATE SET name = $2, title = $3, movement_speed = $4, rank = $5, created_at = $6
+		DO UPDATE SET name = $2, title = $3, locale = $4, movement_speed = $5, rank = $6, updated_at = $7,
+			hp = $8, hp_per_level = $9, mp = $10, mp_per_level = $11, armor = $12, armor_per_level = $13,
+			spell_block = $14, spell_block_per_level = $15, attack_range = $16,
+			attack_damage = $17, attack_damage_per_level = $18, attack_speed = $19, attack_speed_per_level = $20
 	`)
 	if err != nil {
 		tx.Rollback()
@@ -44,37 +104,143 @@ func (r *PostgresChampionRepository) SaveChampions(ctx context.Context, champion
 	}
 	defer stmt.Close()
 
+	historyStmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO champion_stat_history (champion_id, stat, old_value, new_value, changed_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`)
+	if err != nil {
+		tx.Rollback()
+		r.logger.Error("Failed to prepare history statement: %v", err)
+		return err
+	}
+	defer historyStmt.Close()
+
+	now := time.Now()
+	changes := 0
 	for _, champion := range champions {
+		upsertCtx, upsertSpan := startStatementSpan(ctx, "INSERT", "champions")
 		_, err := stmt.ExecContext(
-			ctx,
+			upsertCtx,
 			champion.ChampionID,
 			champion.Name,
 			champion.Title,
+			champion.Locale,
 			champion.MovementSpeed,
 			champion.Rank,
-			time.Now(),
+			now,
+			champion.Stats.HP,
+			champion.Stats.HPPerLevel,
+			champion.Stats.MP,
+			champion.Stats.MPPerLevel,
+			champion.Stats.Armor,
+			champion.Stats.ArmorPerLevel,
+			champion.Stats.SpellBlock,
+			champion.Stats.SpellBlockPerLevel,
+			champion.Stats.AttackRange,
+			champion.Stats.AttackDamage,
+			champion.Stats.AttackDamagePerLevel,
+			champion.Stats.AttackSpeed,
+			champion.Stats.AttackSpeedPerLevel,
 		)
+		upsertSpan.SetAttributes(tracing.String("champion.id", champion.ChampionID))
+		upsertSpan.RecordError(err)
+		upsertSpan.End()
 		if err != nil {
 			tx.Rollback()
 			r.logger.Error("Failed to insert champion %s: %v", champion.Name, err)
 			return err
 		}
+
+		previous, exists := current[champion.ChampionID]
+		if !exists {
+			continue
+		}
+		for _, stat := range trackedStats {
+			oldValue, newValue := stat.value(&previous), stat.value(&champion)
+			if oldValue == newValue {
+				continue
+			}
+
+			historyCtx, historySpan := startStatementSpan(ctx, "INSERT", "champion_stat_history")
+			_, err := historyStmt.ExecContext(historyCtx, champion.ChampionID, stat.name, oldValue, newValue, now)
+			historySpan.RecordError(err)
+			historySpan.End()
+			if err != nil {
+				tx.Rollback()
+				r.logger.Error("Failed to record %s change for champion %s: %v", stat.name, champion.Name, err)
+				return err
+			}
+			changes++
+		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	_, commitSpan := startStatementSpan(ctx, "COMMIT", "")
+	err = tx.Commit()
+	commitSpan.RecordError(err)
+	commitSpan.End()
+	if err != nil {
 		r.logger.Error("Failed to commit transaction: %v", err)
 		return err
 	}
 
-	r.logger.Success("Successfully saved %d champions to database", len(champions))
+	r.logger.Success("Successfully saved %d champions to database with %d recorded changes", len(champions), changes)
 	return nil
 }
 
+// startStatementSpan starts the client span of a single SQL statement, named
+// after its operation and table as in the OpenTelemetry database conventions.
+func startStatementSpan(ctx context.Context, operation, table string) (context.Context, *tracing.Span) {
+	name := operation
+	attributes := []tracing.Attribute{
+		tracing.String("db.system", "postgresql"),
+		tracing.String("db.operation.name", operation),
+	}
+	if table != "" {
+		name += " " + table
+		attributes = append(attributes, tracing.String("db.collection.name", table))
+	}
+	return tracing.Start(ctx, name, tracing.WithSpanKind(tracing.SpanKindClient), tracing.WithAttributes(attributes...))
+}
+
+// saveLockID is the pg_advisory_xact_lock key held by SaveChampions so that
+// concurrent syncs of every replica save one at a time.
+const saveLockID = 72_641_020_902
+
+// lockCurrentChampions takes the save lock until tx finishes and then loads
+// the stored champions keyed by champion ID, so that concurrent syncs cannot
+// interleave their history entries. Row locks would not be enough: two first
+// syncs would both find no rows and both insert every champion.
+func (r *PostgresChampionRepository) lockCurrentChampions(ctx context.Context, tx *sql.Tx) (map[string]entities.ChampionRecord, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, saveLockID); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+championColumns+`
+		FROM champions
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	champions := make(map[string]entities.ChampionRecord)
+	for rows.Next() {
+		var champion entities.ChampionRecord
+		if err := rows.Scan(championFields(&champion)...); err != nil {
+			return nil, err
+		}
+		champions[champion.ChampionID] = champion
+	}
+
+	return champions, rows.Err()
+}
+
 func (r *PostgresChampionRepository) GetChampions(ctx context.Context) ([]entities.ChampionRecord, error) {
 	r.logger.Info("Retrieving champions from database")
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, champion_id, name, title, movement_speed, rank, created_at
+		SELECT `+championColumns+`
 		FROM champions
 		ORDER BY rank ASC
 	`)
@@ -87,18 +253,11 @@ func (r *PostgresChampionRepository) GetChampions(ctx context.Context) ([]entiti
 	var champions []entities.ChampionRecord
 	for rows.Next() {
 		var champion entities.ChampionRecord
-		if err := rows.Scan(
-			&champion.ID,
-			&champion.ChampionID,
-			&champion.Name,
-			&champion.Title,
-			&champion.MovementSpeed,
-			&champion.Rank,
-			&champion.CreatedAt,
-		); err != nil {
+		if err := rows.Scan(championFields(&champion)...); err != nil {
 			r.logger.Error("Failed to scan champion row: %v", err)
 			return nil, err
 		}
+		champion.Stats.MoveSpeed = champion.MovementSpeed
 		champions = append(champions, champion)
 	}
 
@@ -116,18 +275,10 @@ func (r *PostgresChampionRepository) GetChampionByID(ctx context.Context, id str
 
 	var champion entities.ChampionRecord
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, champion_id, name, title, movement_speed, rank, created_at
+		SELECT `+championColumns+`
 		FROM champions
 		WHERE champion_id = $1
-	`, id).Scan(
-		&champion.ID,
-		&champion.ChampionID,
-		&champion.Name,
-		&champion.Title,
-		&champion.MovementSpeed,
-		&champion.Rank,
-		&champion.CreatedAt,
-	)
+	`, id).Scan(championFields(&champion)...)
 
 	if err == sql.ErrNoRows {
 		r.logger.Info("Champion with ID %s not found", id)
@@ -137,6 +288,102 @@ func (r *PostgresChampionRepository) GetChampionByID(ctx context.Context, id str
 		return nil, err
 	}
 
+	champion.Stats.MoveSpeed = champion.MovementSpeed
 	r.logger.Success("Successfully retrieved champion %s from database", champion.Name)
 	return &champion, nil
 }
+
+// championFields returns the scan destinations matching championColumns.
+func championFields(champion *entities.ChampionRecord) []interface{} {
+	return []interface{}{
+		&champion.ID,
+		&champion.ChampionID,
+		&champion.Name,
+		&champion.Title,
+		&champion.Locale,
+		&champion.MovementSpeed,
+		&champion.Rank,
+		&champion.CreatedAt,
+		&champion.UpdatedAt,
+		&champion.Stats.HP,
+		&champion.Stats.HPPerLevel,
+		&champion.Stats.MP,
+		&champion.Stats.MPPerLevel,
+		&champion.Stats.Armor,
+		&champion.Stats.ArmorPerLevel,
+		&champion.Stats.SpellBlock,
+		&champion.Stats.SpellBlockPerLevel,
+		&champion.Stats.AttackRange,
+		&champion.Stats.AttackDamage,
+		&champion.Stats.AttackDamagePerLevel,
+		&champion.Stats.AttackSpeed,
+		&champion.Stats.AttackSpeedPerLevel,
+	}
+}
+
+func (r *PostgresChampionRepository) GetChampionHistory(ctx context.Context, championID string) ([]entities.ChampionStatChange, error) {
+	r.logger.Info("Retrieving history of champion %s from database", championID)
+
+	changes, err := r.queryChanges(ctx, `
+		SELECT id, champion_id, stat, old_value, new_value, changed_at
+		FROM champion_stat_history
+		WHERE champion_id = $1
+		ORDER BY changed_at ASC, id ASC
+	`, championID)
+	if err != nil {
+		r.logger.Error("Failed to query history of champion %s: %v", championID, err)
+		return nil, err
+	}
+
+	r.logger.Success("Successfully retrieved %d history entries for champion %s", len(changes), championID)
+	return changes, nil
+}
+
+// GetChangesBetween lists every recorded change with from <= changed_at < to.
+func (r *PostgresChampionRepository) GetChangesBetween(ctx context.Context, from, to time.Time) ([]entities.ChampionStatChange, error) {
+	r.logger.Info("Retrieving champion changes between %s and %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+
+	changes, err := r.queryChanges(ctx, `
+		SELECT id, champion_id, stat, old_value, new_value, changed_at
+		FROM champion_stat_history
+		WHERE changed_at >= $1 AND changed_at < $2
+		ORDER BY changed_at ASC, id ASC
+	`, from, to)
+	if err != nil {
+		r.logger.Error("Failed to query champion changes: %v", err)
+		return nil, err
+	}
+
+	r.logger.Success("Successfully retrieved %d champion changes", len(changes))
+	return changes, nil
+}
+
+func (r *PostgresChampionRepository) queryChanges(ctx context.Context, query string, args ...interface{}) ([]entities.ChampionStatChange, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []entities.ChampionStatChange
+	for rows.Next() {
+		var change entities.ChampionStatChange
+		var oldValue sql.NullFloat64
+		if err := rows.Scan(
+			&change.ID,
+			&change.ChampionID,
+			&change.Stat,
+			&oldValue,
+			&change.NewValue,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		if oldValue.Valid {
+			change.OldValue = &oldValue.Float64
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
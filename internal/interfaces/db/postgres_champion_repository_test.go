@@ -0,0 +1,206 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+// stored is a champion row returned by the locked SELECT of SaveChampions.
+type stored struct {
+	id            string
+	movementSpeed float64
+	rank          int
+	stats         entities.ChampionStats
+}
+
+func storedRows(champions ...stored) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{
+		"id", "champion_id", "name", "title", "locale", "movement_speed", "rank", "created_at", "updated_at",
+		"hp", "hp_per_level", "mp", "mp_per_level", "armor", "armor_per_level",
+		"spell_block", "spell_block_per_level", "attack_range",
+		"attack_damage", "attack_damage_per_level", "attack_speed", "attack_speed_per_level",
+	})
+	for i, c := range champions {
+		s := c.stats
+		rows.AddRow(i+1, c.id, c.id, "", entities.DefaultLocale, c.movementSpeed, c.rank, time.Time{}, time.Time{},
+			s.HP, s.HPPerLevel, s.MP, s.MPPerLevel, s.Armor, s.ArmorPerLevel,
+			s.SpellBlock, s.SpellBlockPerLevel, s.AttackRange,
+			s.AttackDamage, s.AttackDamagePerLevel, s.AttackSpeed, s.AttackSpeedPerLevel)
+	}
+	return rows
+}
+
+func record(id string, movementSpeed float64, rank int, stats entities.ChampionStats) entities.ChampionRecord {
+	return entities.ChampionRecord{ChampionID: id, Name: id, Locale: entities.DefaultLocale, MovementSpeed: movementSpeed, Rank: rank, Stats: stats}
+}
+
+// change is an expected champion_stat_history row.
+type change struct {
+	championID string
+	stat       string
+	oldValue   float64
+	newValue   float64
+}
+
+var ahriStats = entities.ChampionStats{HP: 590, HPPerLevel: 104, Armor: 21, AttackRange: 550, AttackSpeed: 0.668}
+
+func TestSaveChampionsHistory(t *testing.T) {
+	buffed := ahriStats
+	buffed.HP = 600
+	buffed.ArmorPerLevel = 4.7
+
+	tests := []struct {
+		name      string
+		stored    []stored
+		champions []entities.ChampionRecord
+		changes   map[string][]change
+	}{
+		{
+			name:      "first sync records no history",
+			champions: []entities.ChampionRecord{record("Ahri", 330, 1, ahriStats), record("Zed", 345, 2, ahriStats)},
+		},
+		{
+			name:      "unchanged champion",
+			stored:    []stored{{"Ahri", 330, 1, ahriStats}},
+			champions: []entities.ChampionRecord{record("Ahri", 330, 1, ahriStats)},
+		},
+		{
+			name:      "speed and rank change",
+			stored:    []stored{{"Ahri", 330, 2, ahriStats}, {"Zed", 345, 1, ahriStats}},
+			champions: []entities.ChampionRecord{record("Ahri", 335, 1, ahriStats), record("Zed", 345, 2, ahriStats)},
+			changes: map[string][]change{
+				"Ahri": {{"Ahri", "movement_speed", 330, 335}, {"Ahri", "rank", 2, 1}},
+				"Zed":  {{"Zed", "rank", 1, 2}},
+			},
+		},
+		{
+			name:      "base stats change",
+			stored:    []stored{{"Ahri", 330, 1, ahriStats}},
+			champions: []entities.ChampionRecord{record("Ahri", 330, 1, buffed)},
+			changes: map[string][]change{
+				"Ahri": {{"Ahri", "hp", 590, 600}, {"Ahri", "armor_per_level", 0, 4.7}},
+			},
+		},
+		{
+			name:      "new champion next to a stored one",
+			stored:    []stored{{"Ahri", 330, 1, ahriStats}},
+			champions: []entities.ChampionRecord{record("Ahri", 330, 2, ahriStats), record("Ambessa", 335, 1, ahriStats)},
+			changes: map[string][]change{
+				"Ahri": {{"Ahri", "rank", 1, 2}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMock(t)
+
+			mock.ExpectBegin()
+			mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock($1)")).
+				WithArgs(int64(saveLockID)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery("FROM champions").WillReturnRows(storedRows(tt.stored...))
+			upsert := mock.ExpectPrepare("INSERT INTO champions")
+			history := mock.ExpectPrepare("INSERT INTO champion_stat_history")
+			for _, champion := range tt.champions {
+				upsert.ExpectExec().
+					WithArgs(upsertArgs(champion)...).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				for _, c := range tt.changes[champion.ChampionID] {
+					history.ExpectExec().
+						WithArgs(c.championID, c.stat, c.oldValue, c.newValue, sqlmock.AnyArg()).
+						WillReturnResult(sqlmock.NewResult(0, 1))
+				}
+			}
+			mock.ExpectCommit()
+
+			if err := NewPostgresChampionRepository(db, quietLogger()).SaveChampions(context.Background(), tt.champions); err != nil {
+				t.Fatal(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestSaveChampionsRollsBack(t *testing.T) {
+	failure := errors.New("connection reset")
+
+	tests := []struct {
+		name   string
+		expect func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "lock",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("pg_advisory_xact_lock").WillReturnError(failure)
+			},
+		},
+		{
+			name: "history insert",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec("pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery("FROM champions").WillReturnRows(storedRows(stored{"Ahri", 330, 1, ahriStats}))
+				upsert := mock.ExpectPrepare("INSERT INTO champions")
+				history := mock.ExpectPrepare("INSERT INTO champion_stat_history")
+				upsert.ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+				history.ExpectExec().WillReturnError(failure)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMock(t)
+			mock.ExpectBegin()
+			tt.expect(mock)
+			mock.ExpectRollback()
+
+			err := NewPostgresChampionRepository(db, quietLogger()).SaveChampions(context.Background(),
+				[]entities.ChampionRecord{record("Ahri", 335, 1, ahriStats)})
+			if !errors.Is(err, failure) {
+				t.Errorf("got error %v, want %v", err, failure)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func newMock(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, mock
+}
+
+func upsertArgs(c entities.ChampionRecord) []driver.Value {
+	s := c.Stats
+	return []driver.Value{
+		c.ChampionID, c.Name, c.Title, c.Locale, c.MovementSpeed, int64(c.Rank), sqlmock.AnyArg(),
+		s.HP, s.HPPerLevel, s.MP, s.MPPerLevel, s.Armor, s.ArmorPerLevel,
+		s.SpellBlock, s.SpellBlockPerLevel, s.AttackRange,
+		s.AttackDamage, s.AttackDamagePerLevel, s.AttackSpeed, s.AttackSpeedPerLevel,
+	}
+}
+
+func quietLogger() logger.Logger {
+	return logger.NewJSONLogger(io.Discard, logger.LevelError)
+}
@@ -2,32 +2,109 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
 	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
 )
 
+const (
+	DefaultDataDragonURL = "https://ddragon.leagueoflegends.com"
+	DefaultWorkers       = 8
+)
+
+// FetchMode selects how champion stats are downloaded from Data Dragon.
+type FetchMode string
+
+const (
+	// ModeDetail lists champions from champion.json and downloads one
+	// detail document per champion.
+	ModeDetail FetchMode = "detail"
+	// ModeFull downloads every champion with its stats from the single
+	// championFull.json bundle.
+	ModeFull FetchMode = "full"
+)
+
+func ParseFetchMode(value string) (FetchMode, error) {
+	switch FetchMode(value) {
+	case "", ModeDetail:
+		return ModeDetail, nil
+	case ModeFull:
+		return ModeFull, nil
+	default:
+		return "", fmt.Errorf("unknown data dragon fetch mode %q", value)
+	}
+}
+
+type ChampionRepositoryConfig struct {
+	DataDragonURL string
+	// Version pins the Data Dragon patch. When empty the latest version
+	// listed in versions.json is resolved on every fetch.
+	Version string
+	// Workers bounds the number of concurrent champion detail requests.
+	Workers int
+	// Mode defaults to ModeDetail.
+	Mode FetchMode
+}
+
 type ChampionRepository struct {
-	httpClient client.HTTPClient
-	logger     logger.Logger
-	baseURL    string
-	apiKey     string
+	httpClient    client.HTTPClient
+	logger        logger.Logger
+	dataDragonURL string
+	version       string
+	workers       int
+	mode          FetchMode
+
+	localesMu sync.Mutex
+	locales   []string
 }
 
-func NewChampionRepository(httpClient client.HTTPClient, logger logger.Logger, baseURL string, apiKey string) *ChampionRepository {
+func NewChampionRepository(httpClient client.HTTPClient, logger logger.Logger, config ChampionRepositoryConfig) *ChampionRepository {
+	dataDragonURL := config.DataDragonURL
+	if dataDragonURL == "" {
+		dataDragonURL = DefaultDataDragonURL
+	}
+	workers := config.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	mode := config.Mode
+	if mode == "" {
+		mode = ModeDetail
+	}
+
 	return &ChampionRepository{
-		httpClient: httpClient,
-		logger:     logger,
-		baseURL:    baseURL,
-		apiKey:     apiKey,
+		httpClient:    httpClient,
+		logger:        logger,
+		dataDragonURL: dataDragonURL,
+		version:       config.Version,
+		workers:       workers,
+		mode:          mode,
 	}
 }
 
-func (r *ChampionRepository) GetAllChampions(ctx context.Context) ([]entities.Champion, error) {
-	url := "https://ddragon.leagueoflegends.com/cdn/15.7.1/data/en_US/champion.json"
-	r.logger.Info("Fetching all champions from Data Dragon")
+func (r *ChampionRepository) GetAllChampions(ctx context.Context, locale string) ([]entities.Champion, error) {
+	if err := r.validateLocale(ctx, locale); err != nil {
+		return nil, err
+	}
+
+	version, err := r.resolveVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeFull {
+		return r.getAllChampionsFromBundle(ctx, version, locale)
+	}
+
+	url := fmt.Sprintf("%s/cdn/%s/data/%s/champion.json", r.dataDragonURL, version, locale)
+	r.logger.Info("Fetching all champions from Data Dragon patch %s in %s", version, locale)
 
 	data, err := r.httpClient.Get(ctx, url)
 	if err != nil {
@@ -40,46 +117,284 @@ func (r *ChampionRepository) GetAllChampions(ctx context.Context) ([]entities.Ch
 		r.logger.Error("Failed to parse champion data: %v", err)
 		return nil, err
 	}
+	if championData.Version == "" {
+		championData.Version = version
+	}
 
-	champions := make([]entities.Champion, 0, len(championData.Data))
+	infos := make([]entities.ChampionInfo, 0, len(championData.Data))
 	for _, info := range championData.Data {
-		detailURL := fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/15.7.1/data/en_US/champion/%s.json", info.ID)
-		r.logger.Info("Fetching detailed data for champion: %s", info.Name)
-		
-		detailData, err := r.httpClient.Get(ctx, detailURL)
-		if err != nil {
-			r.logger.Error("Failed to fetch detailed data for champion %s: %v", info.Name, err)
-			continue
-		}
-		
-		var detailChampionData struct {
-			Data map[string]struct {
-				Stats struct {
-					Movespeed float64 `json:"movespeed"`
-				} `json:"stats"`
-			} `json:"data"`
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+
+	champions, err := r.fetchChampionDetails(ctx, championData.Version, locale, infos)
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.Success("Successfully fetched %d champions with stats data", len(champions))
+	return champions, nil
+}
+
+// fetchChampionDetails downloads the detail document of every champion
+// through a bounded pool of workers. Results keep the order of infos and
+// every failure is collected into a single ChampionFetchError.
+func (r *ChampionRepository) fetchChampionDetails(ctx context.Context, version, locale string, infos []entities.ChampionInfo) ([]entities.Champion, error) {
+	workers := r.workers
+	if workers > len(infos) {
+		workers = len(infos)
+	}
+
+	results := make([]entities.Champion, len(infos))
+	failures := make([]error, len(infos))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], failures[i] = r.fetchChampionDetail(ctx, version, locale, infos[i])
+			}
+		}()
+	}
+
+dispatch:
+	for i := range infos {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
 		}
-		
-		if err := client.ParseJSON(detailData, &detailChampionData); err != nil {
-			r.logger.Error("Failed to parse detailed champion data for %s: %v", info.Name, err)
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		r.logger.Error("Champion detail fetch aborted: %v", err)
+		return nil, err
+	}
+
+	fetchErr := &ChampionFetchError{Failures: make(map[string]error)}
+	champions := make([]entities.Champion, 0, len(infos))
+	for i, info := range infos {
+		if failures[i] != nil {
+			fetchErr.Failures[info.ID] = failures[i]
 			continue
 		}
-		
-		movespeed := 0.0
-		if champData, ok := detailChampionData.Data[info.ID]; ok {
-			movespeed = champData.Stats.Movespeed
+		champions = append(champions, results[i])
+	}
+
+	if len(fetchErr.Failures) > 0 {
+		r.logger.Error("Failed to fetch detailed data for %d of %d champions", len(fetchErr.Failures), len(infos))
+		return nil, fetchErr
+	}
+
+	return champions, nil
+}
+
+func (r *ChampionRepository) fetchChampionDetail(ctx context.Context, version, locale string, info entities.ChampionInfo) (entities.Champion, error) {
+	detailURL := fmt.Sprintf("%s/cdn/%s/data/%s/champion/%s.json", r.dataDragonURL, version, locale, info.ID)
+	r.logger.Debug("Fetching detailed data for champion: %s", info.Name)
+
+	detailData, err := r.httpClient.Get(ctx, detailURL)
+	if err != nil {
+		return entities.Champion{}, fmt.Errorf("failed to fetch detailed data: %w", err)
+	}
+
+	var detailChampionData championDocument
+	if err := client.ParseJSON(detailData, &detailChampionData); err != nil {
+		return entities.Champion{}, fmt.Errorf("failed to parse detailed data: %w", err)
+	}
+
+	detail := detailChampionData.Data[info.ID]
+	return newChampion(info, detail.Stats, version, locale), nil
+}
+
+// getAllChampionsFromBundle builds every champion from the championFull.json
+// document, which embeds the stats that ModeDetail fetches one by one.
+func (r *ChampionRepository) getAllChampionsFromBundle(ctx context.Context, version, locale string) ([]entities.Champion, error) {
+	url := fmt.Sprintf("%s/cdn/%s/data/%s/championFull.json", r.dataDragonURL, version, locale)
+	r.logger.Info("Fetching champion bundle from Data Dragon patch %s in %s", version, locale)
+
+	data, err := r.httpClient.Get(ctx, url)
+	if err != nil {
+		r.logger.Error("Failed to fetch champion bundle: %v", err)
+		return nil, err
+	}
+
+	var bundle championDocument
+	if err := client.ParseJSON(data, &bundle); err != nil {
+		r.logger.Error("Failed to parse champion bundle: %v", err)
+		return nil, err
+	}
+	if bundle.Version == "" {
+		bundle.Version = version
+	}
+
+	champions := make([]entities.Champion, 0, len(bundle.Data))
+	for _, detail := range bundle.Data {
+		champions = append(champions, newChampion(detail.ChampionInfo, detail.Stats, bundle.Version, locale))
+	}
+	sort.Slice(champions, func(i, j int) bool {
+		return champions[i].ID < champions[j].ID
+	})
+
+	r.logger.Success("Successfully fetched %d champions with stats data", len(champions))
+	return champions, nil
+}
+
+// resolveVersion returns the pinned patch, or the newest entry of the
+// Data Dragon versions.json manifest when no version is pinned.
+func (r *ChampionRepository) resolveVersion(ctx context.Context) (string, error) {
+	if r.version != "" {
+		return r.version, nil
+	}
+
+	url := fmt.Sprintf("%s/api/versions.json", r.dataDragonURL)
+	r.logger.Info("Resolving latest Data Dragon version")
+
+	data, err := r.httpClient.Get(ctx, url)
+	if err != nil {
+		r.logger.Error("Failed to fetch Data Dragon versions: %v", err)
+		return "", err
+	}
+
+	var versions []string
+	if err := client.ParseJSON(data, &versions); err != nil {
+		r.logger.Error("Failed to parse Data Dragon versions: %v", err)
+		return "", err
+	}
+	if len(versions) == 0 {
+		r.logger.Error("Data Dragon versions manifest is empty")
+		return "", fmt.Errorf("data dragon versions manifest is empty")
+	}
+
+	r.logger.Info("Using Data Dragon version %s", versions[0])
+	return versions[0], nil
+}
+
+// GetLocales returns the locales listed in the Data Dragon languages.json
+// manifest. The list is fetched once and kept for the life of the
+// repository.
+func (r *ChampionRepository) GetLocales(ctx context.Context) ([]string, error) {
+	r.localesMu.Lock()
+	defer r.localesMu.Unlock()
+
+	if r.locales != nil {
+		return append([]string(nil), r.locales...), nil
+	}
+
+	url := fmt.Sprintf("%s/cdn/languages.json", r.dataDragonURL)
+	r.logger.Info("Fetching Data Dragon languages")
+
+	data, err := r.httpClient.Get(ctx, url)
+	if err != nil {
+		r.logger.Error("Failed to fetch Data Dragon languages: %v", err)
+		return nil, err
+	}
+
+	var locales []string
+	if err := client.ParseJSON(data, &locales); err != nil {
+		r.logger.Error("Failed to parse Data Dragon languages: %v", err)
+		return nil, err
+	}
+	if len(locales) == 0 {
+		r.logger.Error("Data Dragon languages manifest is empty")
+		return nil, fmt.Errorf("data dragon languages manifest is empty")
+	}
+
+	r.locales = locales
+	return append([]string(nil), locales...), nil
+}
+
+// validateLocale checks locale against languages.json. DefaultLocale is
+// always accepted so that the default path does not depend on the manifest.
+func (r *ChampionRepository) validateLocale(ctx context.Context, locale string) error {
+	if locale == entities.DefaultLocale {
+		return nil
+	}
+
+	locales, err := r.GetLocales(ctx)
+	if err != nil {
+		return err
+	}
+	for _, supported := range locales {
+		if supported == locale {
+			return nil
 		}
-		
-		champion := entities.Champion{
-			ID:           info.ID,
-			Key:          info.Key,
-			Name:         info.Name,
-			Title:        info.Title,
-			MovementSpeed: movespeed,
+	}
+	return fmt.Errorf("%w: unsupported locale %q", usecases.ErrInvalidParameter, locale)
+}
+
+// ChampionFetchError reports every champion whose detail document could not
+// be fetched or parsed, keyed by champion ID.
+type ChampionFetchError struct {
+	Failures map[string]error
+}
+
+func (e *ChampionFetchError) Error() string {
+	ids := e.ids()
+	messages := make([]string, 0, len(ids))
+	for _, id := range ids {
+		messages = append(messages, fmt.Sprintf("%s: %v", id, e.Failures[id]))
+	}
+	return fmt.Sprintf("failed to fetch %d champions: %s", len(ids), strings.Join(messages, "; "))
+}
+
+// Is reports whether the failure of any champion matches target, so that
+// errors.Is can classify the cause whichever champion it happened to.
+func (e *ChampionFetchError) Is(target error) bool {
+	for _, id := range e.ids() {
+		if errors.Is(e.Failures[id], target) {
+			return true
 		}
-		champions = append(champions, champion)
 	}
+	return false
+}
 
-	r.logger.Success("Successfully fetched %d champions with movement speed data", len(champions))
-	return champions, nil
+// As finds the first failure, by champion ID, that matches target.
+func (e *ChampionFetchError) As(target interface{}) bool {
+	for _, id := range e.ids() {
+		if errors.As(e.Failures[id], target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *ChampionFetchError) ids() []string {
+	ids := make([]string, 0, len(e.Failures))
+	for id := range e.Failures {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// championDocument matches both the per-champion detail files and the
+// championFull.json bundle, which share the same layout.
+type championDocument struct {
+	Version string `json:"version"`
+	Data    map[string]struct {
+		entities.ChampionInfo
+		Stats entities.ChampionStats `json:"stats"`
+	} `json:"data"`
+}
+
+func newChampion(info entities.ChampionInfo, stats entities.ChampionStats, version, locale string) entities.Champion {
+	return entities.Champion{
+		ID:            info.ID,
+		Key:           info.Key,
+		Name:          info.Name,
+		Title:         info.Title,
+		Tags:          info.Tags,
+		MovementSpeed: stats.MoveSpeed,
+		Stats:         stats,
+		Version:       version,
+		Locale:        locale,
+	}
 }
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+const fixtureVersion = "15.7.1"
+
+// newDataDragonServer serves the documents under testdata/ddragon as the
+// en_US data of fixtureVersion.
+func newDataDragonServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	prefix := "/cdn/" + fixtureVersion + "/data/" + entities.DefaultLocale + "/"
+	mux.Handle(prefix, http.StripPrefix(prefix, http.FileServer(http.Dir("testdata/ddragon"))))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestRepository(dataDragonURL string, mode FetchMode) *ChampionRepository {
+	return NewChampionRepository(
+		client.NewHTTPClient(5*time.Second),
+		logger.NewJSONLogger(io.Discard, logger.LevelError),
+		ChampionRepositoryConfig{
+			DataDragonURL: dataDragonURL,
+			Version:       fixtureVersion,
+			Workers:       2,
+			Mode:          mode,
+		},
+	)
+}
+
+func TestGetAllChampionsModesAgree(t *testing.T) {
+	server := newDataDragonServer(t)
+
+	detail, err := newTestRepository(server.URL, ModeDetail).GetAllChampions(context.Background(), entities.DefaultLocale)
+	if err != nil {
+		t.Fatalf("detail mode: %v", err)
+	}
+	full, err := newTestRepository(server.URL, ModeFull).GetAllChampions(context.Background(), entities.DefaultLocale)
+	if err != nil {
+		t.Fatalf("full mode: %v", err)
+	}
+
+	if len(detail) != 3 {
+		t.Fatalf("detail mode returned %d champions, want 3", len(detail))
+	}
+	if !reflect.DeepEqual(detail, full) {
+		t.Errorf("modes disagree:\ndetail: %+v\nfull:   %+v", detail, full)
+	}
+
+	wukong := detail[2]
+	if wukong.ID != "MonkeyKing" || wukong.Name != "Wukong" || wukong.MovementSpeed != 340 || wukong.Stats.HPPerLevel != 99 {
+		t.Errorf("unexpected champion %+v", wukong)
+	}
+	if wukong.Version != fixtureVersion || wukong.Locale != entities.DefaultLocale {
+		t.Errorf("champion version %q and locale %q, want %q and %q", wukong.Version, wukong.Locale, fixtureVersion, entities.DefaultLocale)
+	}
+}
+
+func TestChampionFetchErrorMatchesEveryFailure(t *testing.T) {
+	err := error(&ChampionFetchError{Failures: map[string]error{
+		"Aatrox": fmt.Errorf("failed to fetch detailed data: %w", &client.StatusError{StatusCode: http.StatusNotFound}),
+		"Zed":    fmt.Errorf("failed to fetch detailed data: %w", context.DeadlineExceeded),
+	}})
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("errors.Is does not find the deadline of the last champion")
+	}
+	if errors.Is(err, context.Canceled) {
+		t.Error("errors.Is matches an error no champion failed with")
+	}
+	var statusErr *client.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("errors.As found %v, want the status of Aatrox", statusErr)
+	}
+}
+
+func TestGetAllChampionsStopsFetchingWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var details int32
+	files := http.FileServer(http.Dir("testdata/ddragon"))
+	prefix := "/cdn/" + fixtureVersion + "/data/" + entities.DefaultLocale
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, prefix+"/champion/") {
+			// The second detail request stands for a client going away
+			// while the fan-out is in flight.
+			if atomic.AddInt32(&details, 1) == 2 {
+				cancel()
+				select {
+				case <-r.Context().Done():
+					return
+				case <-time.After(2 * time.Second):
+				}
+			}
+		}
+		http.StripPrefix(prefix, files).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	repo := NewChampionRepository(
+		client.NewHTTPClient(5*time.Second, client.WithRetryPolicy(client.DefaultRetryPolicy())),
+		logger.NewJSONLogger(io.Discard, logger.LevelError),
+		ChampionRepositoryConfig{DataDragonURL: server.URL, Version: fixtureVersion, Workers: 1},
+	)
+
+	start := time.Now()
+	if _, err := repo.GetAllChampions(ctx, entities.DefaultLocale); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("returned after %s, want the in-flight request aborted", elapsed)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&details); got != 2 {
+		t.Errorf("%d detail requests, want 2: none after the cancellation", got)
+	}
+}
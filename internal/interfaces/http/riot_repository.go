@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+// DefaultRiotURLTemplate builds the host of a platform or regional routing
+// value by replacing {route}.
+const DefaultRiotURLTemplate = "https://{route}.api.riotgames.com"
+
+// Rate limit method names, matching the method scopes Riot reports in
+// X-Method-Rate-Limit.
+const (
+	methodAccountByRiotID    = "account-v1.by-riot-id"
+	methodTopChampionMastery = "champion-mastery-v4.top-by-puuid"
+)
+
+type RiotRepositoryConfig struct {
+	// URLTemplate defaults to DefaultRiotURLTemplate.
+	URLTemplate string
+	APIKey      string
+}
+
+// RiotRepository reads player data from the Riot Games API, authenticating
+// every request with the X-Riot-Token header. Riot enforces rate limits per
+// routing value, so every platform and region gets its own HTTP client from
+// newClient, created on first use.
+type RiotRepository struct {
+	newClient   func() client.HTTPClient
+	logger      logger.Logger
+	urlTemplate string
+	apiKey      string
+
+	mu      sync.Mutex
+	clients map[string]client.HTTPClient
+}
+
+func NewRiotRepository(newClient func() client.HTTPClient, logger logger.Logger, config RiotRepositoryConfig) *RiotRepository {
+	urlTemplate := config.URLTemplate
+	if urlTemplate == "" {
+		urlTemplate = DefaultRiotURLTemplate
+	}
+
+	return &RiotRepository{
+		newClient:   newClient,
+		logger:      logger,
+		urlTemplate: urlTemplate,
+		apiKey:      config.APIKey,
+		clients:     make(map[string]client.HTTPClient),
+	}
+}
+
+func (r *RiotRepository) GetAccountByRiotID(ctx context.Context, platform entities.Platform, gameName, tagLine string) (*entities.Account, error) {
+	route := accountRoute(platform.Region())
+	endpoint := fmt.Sprintf("%s/riot/account/v1/accounts/by-riot-id/%s/%s",
+		r.baseURL(route), url.PathEscape(gameName), url.PathEscape(tagLine))
+	r.logger.Info("Resolving Riot ID %s#%s on %s", gameName, tagLine, route)
+
+	var account entities.Account
+	if err := r.getJSON(ctx, route, methodAccountByRiotID, endpoint, fmt.Sprintf("Riot ID %q", gameName+"#"+tagLine), &account); err != nil {
+		r.logger.Error("Failed to resolve Riot ID %s#%s: %v", gameName, tagLine, err)
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *RiotRepository) GetTopChampionMasteries(ctx context.Context, platform entities.Platform, puuid string, count int) ([]entities.ChampionMastery, error) {
+	route := string(platform)
+	endpoint := fmt.Sprintf("%s/lol/champion-mastery/v4/champion-masteries/by-puuid/%s/top?count=%d",
+		r.baseURL(route), url.PathEscape(puuid), count)
+	r.logger.Info("Fetching top %d champion masteries on %s", count, route)
+
+	var masteries []entities.ChampionMastery
+	if err := r.getJSON(ctx, route, methodTopChampionMastery, endpoint, "champion masteries", &masteries); err != nil {
+		r.logger.Error("Failed to fetch champion masteries: %v", err)
+		return nil, err
+	}
+
+	return masteries, nil
+}
+
+// accountRoute returns the routing value serving account-v1 for region.
+// Accounts are shared by every region, but account-v1 is not served by sea.
+func accountRoute(region entities.Region) string {
+	if region == entities.RegionSEA {
+		return string(entities.RegionAsia)
+	}
+	return string(region)
+}
+
+func (r *RiotRepository) baseURL(route string) string {
+	return strings.ReplaceAll(r.urlTemplate, "{route}", route)
+}
+
+func (r *RiotRepository) clientFor(route string) client.HTTPClient {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	httpClient, ok := r.clients[route]
+	if !ok {
+		httpClient = r.newClient()
+		r.clients[route] = httpClient
+	}
+	return httpClient
+}
+
+// getJSON sends an authenticated GET request and decodes the response into
+// v. A 404 is reported as usecases.ErrNotFound for resource, which names what
+// was looked up for the caller without exposing the Riot API endpoint.
+func (r *RiotRepository) getJSON(ctx context.Context, route, method, endpoint, resource string, v interface{}) error {
+	req, err := http.NewRequestWithContext(client.WithRateLimitMethod(ctx, method), http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Riot-Token", r.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := r.clientFor(route).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("%w: %s", usecases.ErrNotFound, resource)
+	default:
+		io.Copy(io.Discard, resp.Body)
+		return &client.StatusError{StatusCode: resp.StatusCode}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
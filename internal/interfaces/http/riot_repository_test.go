@@ -0,0 +1,41 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marcopaulosilva/poc_devin/internal/domain/entities"
+	"github.com/marcopaulosilva/poc_devin/internal/domain/usecases"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/client"
+	"github.com/marcopaulosilva/poc_devin/internal/infrastructure/logger"
+)
+
+func TestRiotRepositoryNotFoundHidesEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	repo := NewRiotRepository(
+		func() client.HTTPClient { return client.NewHTTPClient(5 * time.Second) },
+		logger.NewJSONLogger(io.Discard, logger.LevelError),
+		RiotRepositoryConfig{URLTemplate: server.URL + "/{route}", APIKey: "key"},
+	)
+
+	_, err := repo.GetAccountByRiotID(context.Background(), entities.PlatformNA1, "Faker", "KR1")
+	if !errors.Is(err, usecases.ErrNotFound) {
+		t.Fatalf("got error %v, want %v", err, usecases.ErrNotFound)
+	}
+	if message := err.Error(); strings.Contains(message, "/riot/") || strings.Contains(message, "americas") {
+		t.Errorf("error %q exposes the Riot API endpoint", message)
+	}
+
+	_, err = repo.GetTopChampionMasteries(context.Background(), entities.PlatformNA1, "puuid", 3)
+	if !errors.Is(err, usecases.ErrNotFound) || strings.Contains(err.Error(), "/lol/") {
+		t.Errorf("got error %v, want a not found error without the endpoint", err)
+	}
+}